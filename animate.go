@@ -0,0 +1,196 @@
+package smartSVG
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Values of Timing.Fill
+const (
+	Freeze = "freeze"
+	Remove = "remove"
+)
+
+// Values of Timing.CalcMode
+const (
+	CalcDiscrete = "discrete"
+	CalcLinear   = "linear"
+	CalcPaced    = "paced"
+	CalcSpline   = "spline"
+)
+
+// RepeatCount of animations repeating forever
+const Indefinite = -1
+
+// Timing of an animation. Zero values are left out and get the svg defaults.
+// Ref http://www.w3.org/TR/SVG11/animate.html#TimingAttributes
+type Timing struct {
+	Begin       time.Duration // Offset from document start
+	BeginOn     string        // Event starting the animation, like "click". Begin is added as offset.
+	Dur         time.Duration
+	RepeatCount float64 // Indefinite repeats forever
+	Fill        string  // Freeze or Remove
+	CalcMode    string
+	KeyTimes    []float64    // One per value, from 0 to 1
+	KeySplines  [][4]float64 // One per interval between key times, or between values without key times. Only used with CalcSpline.
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprint(d.Seconds()) + "s"
+}
+
+func joinFloats(vals []float64, sep string) string {
+	str := make([]string, len(vals))
+	for i, v := range vals {
+		str[i] = exactFormat.Format(v)
+	}
+	return strings.Join(str, sep)
+}
+
+// Check timing against count values. Values are not checked if count is negative.
+func (t Timing) check(count int) error {
+	if len(t.KeyTimes) != 0 {
+		if count >= 0 && len(t.KeyTimes) != count {
+			return errors.New("Got " + fmt.Sprint(len(t.KeyTimes)) + " key times for " + fmt.Sprint(count) + " values")
+		}
+		last := 0.0
+		for i, v := range t.KeyTimes {
+			if v < last || v > 1 {
+				return errors.New("Key times must increase from 0 to 1, got " + fmt.Sprint(t.KeyTimes))
+			}
+			if i == 0 && v != 0 {
+				return errors.New("First key time must be 0")
+			}
+			last = v
+		}
+		if t.CalcMode != CalcDiscrete && t.CalcMode != CalcPaced && last != 1 {
+			return errors.New("Last key time must be 1")
+		}
+	}
+	if len(t.KeySplines) != 0 {
+		switch {
+		case t.CalcMode != CalcSpline:
+			return errors.New("Key splines are only used with calcMode spline")
+		case len(t.KeyTimes) != 0 && len(t.KeySplines) != len(t.KeyTimes)-1:
+			return errors.New("Got " + fmt.Sprint(len(t.KeySplines)) + " key splines for " + fmt.Sprint(len(t.KeyTimes)) + " key times")
+		case len(t.KeyTimes) == 0 && count >= 0 && len(t.KeySplines) != count-1:
+			// Without key times, the values are spread evenly over the duration
+			return errors.New("Got " + fmt.Sprint(len(t.KeySplines)) + " key splines for " + fmt.Sprint(count) + " values")
+		}
+		for _, spline := range t.KeySplines {
+			for _, v := range spline {
+				if v < 0 || v > 1 {
+					return errors.New("Key spline control points must be between 0 and 1, got " + fmt.Sprint(spline))
+				}
+			}
+		}
+	} else if t.CalcMode == CalcSpline {
+		return errors.New("calcMode spline needs key splines")
+	}
+	return nil
+}
+
+// Set timing attributes of animation
+func (t Timing) set(a Att) {
+	switch {
+	case t.BeginOn != "" && t.Begin != 0:
+		a["begin"] = t.BeginOn + "+" + seconds(t.Begin)
+	case t.BeginOn != "":
+		a["begin"] = t.BeginOn
+	case t.Begin != 0:
+		a["begin"] = seconds(t.Begin)
+	}
+	if t.Dur != 0 {
+		a["dur"] = seconds(t.Dur)
+	}
+	switch {
+	case t.RepeatCount == Indefinite:
+		a["repeatCount"] = "indefinite"
+	case t.RepeatCount != 0:
+		a["repeatCount"] = fmt.Sprint(t.RepeatCount)
+	}
+	if t.Fill != "" {
+		a["fill"] = t.Fill
+	}
+	if t.CalcMode != "" {
+		a["calcMode"] = t.CalcMode
+	}
+	if len(t.KeyTimes) != 0 {
+		a["keyTimes"] = joinFloats(t.KeyTimes, ";")
+	}
+	if len(t.KeySplines) != 0 {
+		splines := make([]string, len(t.KeySplines))
+		for i, v := range t.KeySplines {
+			splines[i] = joinFloats(v[:], " ")
+		}
+		a["keySplines"] = strings.Join(splines, ";")
+	}
+}
+
+// Create animation element of s after checking timing
+func (s *SVG) animation(tag string, values []string, t Timing, a Att) (*SVG, error) {
+	count := -1
+	if values != nil {
+		if len(values) == 0 {
+			return nil, errors.New("Got no values to animate")
+		}
+		count = len(values)
+	}
+	if err := t.check(count); err != nil {
+		return nil, err
+	}
+	g := s.newGroup(tag, a)
+	t.set(g.a)
+	if values != nil {
+		g.a["values"] = strings.Join(values, ";")
+	}
+	return g, nil
+}
+
+// Animate attribute of s through values. Ref http://www.w3.org/TR/SVG11/animate.html#AnimateElement
+func (s *SVG) Animate(attribute string, values []string, t Timing, a Att) (*SVG, error) {
+	g, err := s.animation("animate", values, t, a)
+	if err != nil {
+		return nil, err
+	}
+	g.a["attributeName"] = attribute
+	return g, nil
+}
+
+// Animate transform of s through values of transform type translate, scale, rotate, skewX or skewY,
+// like "0 0;10 0" for translate. Ref http://www.w3.org/TR/SVG11/animate.html#AnimateTransformElement
+func (s *SVG) AnimateTransform(typ string, values []string, t Timing, a Att) (*SVG, error) {
+	g, err := s.animation("animateTransform", values, t, a)
+	if err != nil {
+		return nil, err
+	}
+	g.a["attributeName"] = "transform"
+	g.a["type"] = typ
+	return g, nil
+}
+
+// Move s along path with given id. Ref http://www.w3.org/TR/SVG11/animate.html#AnimateMotionElement
+func (s *SVG) AnimateMotion(pathID string, t Timing, a Att) (*SVG, error) {
+	g, err := s.animation("animateMotion", nil, t, a)
+	if err != nil {
+		return nil, err
+	}
+	g.newGroup("mpath", Att{"xlink:href": "#" + pathID})
+	return g, nil
+}
+
+// Set attribute of s to value for the duration of t. Ref http://www.w3.org/TR/SVG11/animate.html#SetElement
+func (s *SVG) Set(attribute, to string, t Timing, a Att) (*SVG, error) {
+	if len(t.KeyTimes) != 0 || len(t.KeySplines) != 0 || t.CalcMode != "" {
+		return nil, errors.New("Set takes no key times, key splines or calcMode")
+	}
+	g, err := s.animation("set", nil, t, a)
+	if err != nil {
+		return nil, err
+	}
+	g.a["attributeName"] = attribute
+	g.a["to"] = to
+	return g, nil
+}
@@ -0,0 +1,77 @@
+package smartSVG
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimingCheck(t *testing.T) {
+	spline := [4]float64{0.4, 0, 0.2, 1}
+	tests := []struct {
+		t     Timing
+		count int
+		ok    bool
+	}{
+		{Timing{}, 2, true},
+		{Timing{KeyTimes: []float64{0, 0.5, 1}}, 3, true},
+		{Timing{KeyTimes: []float64{0, 0.5, 1}}, 2, false},
+		{Timing{KeyTimes: []float64{0.1, 1}}, 2, false},
+		{Timing{KeyTimes: []float64{0, 0.6, 0.5, 1}}, 4, false},
+		{Timing{KeyTimes: []float64{0, 0.5}}, 2, false},
+		{Timing{KeyTimes: []float64{0, 0.5}, CalcMode: CalcDiscrete}, 2, true},
+		{Timing{CalcMode: CalcSpline}, 2, false},
+		{Timing{KeySplines: [][4]float64{spline}}, 2, false},
+		{Timing{CalcMode: CalcSpline, KeySplines: [][4]float64{spline, spline}}, 3, true},
+		{Timing{CalcMode: CalcSpline, KeySplines: [][4]float64{spline, spline}}, 2, false},
+		{Timing{CalcMode: CalcSpline, KeyTimes: []float64{0, 0.3, 1}, KeySplines: [][4]float64{spline, spline}}, 3, true},
+		{Timing{CalcMode: CalcSpline, KeyTimes: []float64{0, 1}, KeySplines: [][4]float64{spline, spline}}, 2, false},
+		{Timing{CalcMode: CalcSpline, KeySplines: [][4]float64{{0, 0, 1.5, 1}}}, 2, false},
+	}
+	for _, test := range tests {
+		if err := test.t.check(test.count); (err == nil) != test.ok {
+			t.Errorf("Check of %+v for %d values gave error %v", test.t, test.count, err)
+		}
+	}
+}
+
+func TestAnimate(t *testing.T) {
+	c := New(10, 10).Circle(5, 5, 1, nil)
+	g, err := c.Animate("r", []string{"1", "5", "1"}, Timing{
+		BeginOn:     "click",
+		Begin:       500 * time.Millisecond,
+		Dur:         1500 * time.Millisecond,
+		RepeatCount: Indefinite,
+		CalcMode:    CalcSpline,
+		KeyTimes:    []float64{0, 0.25, 1},
+		KeySplines:  [][4]float64{{0.4, 0, 0.2, 1}, {0, 0, 1, 1}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Att{
+		"attributeName": "r",
+		"values":        "1;5;1",
+		"begin":         "click+0.5s",
+		"dur":           "1.5s",
+		"repeatCount":   "indefinite",
+		"calcMode":      "spline",
+		"keyTimes":      "0;0.25;1",
+		"keySplines":    "0.4 0 0.2 1;0 0 1 1",
+	}
+	if g.a.String() != want.String() {
+		t.Errorf("Animation has attributes %s, want %s", g.a, want)
+	}
+	if _, err := c.Animate("r", []string{}, Timing{}, nil); err == nil {
+		t.Error("Animation without values gave no error")
+	}
+	if _, err := c.Set("fill", "red", Timing{CalcMode: CalcLinear}, nil); err == nil {
+		t.Error("Set with calcMode gave no error")
+	}
+	m, err := c.AnimateMotion("track", Timing{Dur: time.Second, Fill: Freeze}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.mids) != 1 || m.mids[0].tag != "mpath" || m.mids[0].a["xlink:href"] != "#track" {
+		t.Errorf("Motion has content %v, want mpath to #track", m.mids)
+	}
+}
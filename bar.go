@@ -0,0 +1,112 @@
+package smartSVG
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Options of BarChart. The zero value gives vertical bars grouped side by side.
+type BarOptions struct {
+	Stacked    bool    // Stack the series of each category instead of placing them side by side
+	Horizontal bool    // Draw bars from left to right, with categories down the left side
+	BarWidth   float64 // Part of each category covered by its bars, between 0 and 1. 0 gives 0.8.
+	Gap        float64 // Part of the width of each grouped bar left empty between it and its neighbours, between 0 and 1
+}
+
+// Draw chart with one bar per value, in categories given by labels. Each series holds one value per category.
+// Bars start at zero, so negative values go downwards, or to the left if horizontal.
+// The chart works with Legend and SeriesFill like Diagram.
+func (s *SVG) BarChart(x, y, width, height int, labels []string, series [][]float64, title string, opts BarOptions) (*SVG, error) {
+	switch {
+	case len(labels) == 0:
+		return nil, errors.New("Got no categories")
+	case len(series) == 0:
+		return nil, errors.New("Got no series")
+	case opts.BarWidth < 0 || opts.BarWidth > 1:
+		return nil, errors.New("Bar width " + fmt.Sprint(opts.BarWidth) + " is not between 0 and 1")
+	case opts.Gap < 0 || opts.Gap >= 1:
+		return nil, errors.New("Gap " + fmt.Sprint(opts.Gap) + " is not between 0 and 1")
+	}
+	for i, vals := range series {
+		if len(vals) != len(labels) {
+			return nil, errors.New("Series " + fmt.Sprint(i) + " has " + fmt.Sprint(len(vals)) + " values for " + fmt.Sprint(len(labels)) + " categories")
+		}
+		for j, v := range vals {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return nil, errors.New("Value " + fmt.Sprint(v) + " of " + labels[j] + " in series " + fmt.Sprint(i) + " is not a number")
+			}
+		}
+	}
+	barWidth := opts.BarWidth
+	if barWidth == 0 {
+		barWidth = 0.8
+	}
+
+	// Find range of values, which always includes the baseline
+	var lo, hi float64
+	for i := range labels {
+		var pos, neg float64
+		for _, vals := range series {
+			switch {
+			case !opts.Stacked:
+				pos, neg = math.Max(pos, vals[i]), math.Min(neg, vals[i])
+			case vals[i] > 0:
+				pos += vals[i]
+			default:
+				neg += vals[i]
+			}
+		}
+		lo, hi = math.Min(lo, neg), math.Max(hi, pos)
+	}
+
+	c, err := s.newChart(x, y, width, height, title)
+	if err != nil {
+		return nil, err
+	}
+	horizontal := opts.Horizontal
+	length := c.height
+	if horizontal {
+		length = c.width
+	}
+	sc := niceScale(lo, hi, length)
+	c.valueAxis(sc, horizontal)
+	c.categoryAxis(labels, horizontal)
+
+	slot := c.slot(len(labels), horizontal)
+	band := slot * barWidth
+	bar := band
+	if !opts.Stacked {
+		bar /= float64(len(series))
+	}
+
+	// Tops of the stacks of positive and negative values of each category
+	pos, neg := make([]float64, len(labels)), make([]float64, len(labels))
+	for j, vals := range series {
+		g := c.series()
+		for i, v := range vals {
+			p, w := float64(i)*slot+(slot-band)/2, bar
+			var base float64
+			switch {
+			case !opts.Stacked:
+				p += float64(j)*bar + bar*opts.Gap/2
+				w *= 1 - opts.Gap
+			case v > 0:
+				base, pos[i] = pos[i], pos[i]+v
+			default:
+				base, neg[i] = neg[i], neg[i]+v
+			}
+			v1, v2 := c.valuePos(sc, base, horizontal), c.valuePos(sc, base+v, horizontal)
+			var r *SVG
+			if horizontal {
+				r = g.RectF(math.Min(v1, v2), p, math.Abs(v2-v1), w, nil)
+			} else {
+				r = g.RectF(p, math.Min(v1, v2), w, math.Abs(v2-v1), nil)
+			}
+			r.Title(labels[i] + ": " + exactFormat.Format(v))
+		}
+	}
+	c.baseline(sc, 0, horizontal)
+	c.frame()
+	return c.top, nil
+}
@@ -0,0 +1,66 @@
+package smartSVG
+
+import (
+	"math"
+	"testing"
+)
+
+// Position and size of the bars of series i of chart c
+func bars(t *testing.T, c *SVG, i int) [][4]float64 {
+	var boxes [][4]float64
+	for _, r := range c.series()[i].FindGroups("rect") {
+		vals, err := r.nums("x", "y", "width", "height")
+		if err != nil {
+			t.Fatal(err)
+		}
+		boxes = append(boxes, [4]float64{vals[0], vals[1], vals[2], vals[3]})
+	}
+	return boxes
+}
+
+func TestBarChart(t *testing.T) {
+	labels := []string{"a", "b"}
+	values := [][]float64{{2, -1}, {1, 3}}
+
+	grouped, err := New(400, 300).BarChart(0, 0, 400, 300, labels, values, "Grouped", BarOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, second := bars(t, grouped, 0), bars(t, grouped, 1)
+	if math.Abs(first[0][0]+first[0][2]-second[0][0]) > 1e-9 || first[0][2] != second[0][2] {
+		t.Errorf("Grouped bars %v and %v are not side by side", first[0], second[0])
+	}
+	if math.Abs(first[0][3]-2*second[0][3]) > 1e-9 || math.Abs(first[1][3]-second[0][3]) > 1e-9 {
+		t.Errorf("Bar heights %v and %v are not proportional to the values", first, second)
+	}
+	if base := first[0][1] + first[0][3]; math.Abs(first[1][1]-base) > 1e-9 {
+		t.Errorf("Negative bar %v does not start at the baseline %v", first[1], base)
+	}
+
+	stacked, err := New(400, 300).BarChart(0, 0, 400, 300, labels, values, "Stacked", BarOptions{Stacked: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, second = bars(t, stacked, 0), bars(t, stacked, 1)
+	if first[0][0] != second[0][0] || math.Abs(first[0][1]-second[0][1]-second[0][3]) > 1e-9 {
+		t.Errorf("Stacked bar %v is not on top of %v", second[0], first[0])
+	}
+
+	horizontal, err := New(400, 300).BarChart(0, 0, 400, 300, labels, values, "Horizontal", BarOptions{Horizontal: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first = bars(t, horizontal, 0)
+	if math.Abs(first[1][0]+first[1][2]-first[0][0]) > 1e-9 || first[0][1] >= first[1][1] {
+		t.Errorf("Horizontal bars %v do not go left and right from the baseline, in category order", first)
+	}
+
+	for _, opts := range []BarOptions{{BarWidth: 2}, {Gap: 1}} {
+		if _, err := New(400, 300).BarChart(0, 0, 400, 300, labels, values, "", opts); err == nil {
+			t.Errorf("BarChart with options %+v gave no error", opts)
+		}
+	}
+	if _, err := New(400, 300).BarChart(0, 0, 400, 300, labels, [][]float64{{1}}, "", BarOptions{}); err == nil {
+		t.Error("BarChart with too few values gave no error")
+	}
+}
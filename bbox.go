@@ -0,0 +1,470 @@
+package smartSVG
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Axis aligned rectangle in user space
+type Box struct {
+	X, Y, Width, Height float64
+}
+
+// Extent of points, empty until the first point is added
+type bounds struct {
+	minX, minY, maxX, maxY float64
+	ok                     bool
+}
+
+func (b *bounds) add(x, y float64) {
+	if !b.ok {
+		b.minX, b.minY, b.maxX, b.maxY, b.ok = x, y, x, y, true
+		return
+	}
+	b.minX, b.maxX = math.Min(b.minX, x), math.Max(b.maxX, x)
+	b.minY, b.maxY = math.Min(b.minY, y), math.Max(b.maxY, y)
+}
+
+// Add corners of c mapped through t
+func (b *bounds) union(c bounds, t Transform) {
+	if !c.ok {
+		return
+	}
+	for _, p := range [][2]float64{{c.minX, c.minY}, {c.maxX, c.minY}, {c.minX, c.maxY}, {c.maxX, c.maxY}} {
+		b.add(t.Apply(p[0], p[1]))
+	}
+}
+
+func (b bounds) box() Box {
+	return Box{b.minX, b.minY, b.maxX - b.minX, b.maxY - b.minY}
+}
+
+// Elements which are not rendered where they are placed
+var notRendered = map[string]bool{
+	"defs": true, "symbol": true, "marker": true, "clipPath": true, "mask": true, "pattern": true,
+	"linearGradient": true, "radialGradient": true, "filter": true, "title": true, "desc": true,
+	"metadata": true, "style": true, "script": true,
+}
+
+// Default font size of text
+const defaultFontSize = 16
+
+// Read length attribute. Missing attributes give def. Percentages can not be read without reference, see length.
+func (s *SVG) num(key string, def float64) (float64, error) {
+	return s.length(key, def, math.NaN())
+}
+
+// Read length attribute, where percentages are relative to ref. Missing attributes give def.
+func (s *SVG) length(key string, def, ref float64) (float64, error) {
+	v, ok := s.a[key]
+	if !ok {
+		return def, nil
+	}
+	switch n := v.(type) {
+	case int:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	}
+	f, err := parseLength(fmt.Sprint(v), ref)
+	if err != nil {
+		return 0, errors.New("Could not read " + key + " of <" + s.tag + ">: " + fmt.Sprint(v))
+	}
+	return f, nil
+}
+
+// Size of units in user units, with 96 user units per inch. Font relative units use the default font size.
+// Ref https://www.w3.org/TR/css-values-3/#absolute-lengths
+var unitSizes = map[string]float64{
+	"px": 1, "in": 96, "cm": 96 / 2.54, "mm": 96 / 25.4, "pt": 96.0 / 72, "pc": 96.0 / 6,
+	"em": defaultFontSize, "ex": defaultFontSize / 2,
+}
+
+// Parse length given as number with optional unit or percentage. Percentages are relative to ref,
+// and can not be read if ref is NaN.
+func parseLength(str string, ref float64) (float64, error) {
+	str = strings.TrimSpace(str)
+	size := 1.0
+	if strings.HasSuffix(str, "%") {
+		if math.IsNaN(ref) {
+			return 0, errors.New("Percentage " + str + " has no reference")
+		}
+		str, size = str[:len(str)-1], ref/100
+	} else if len(str) > 2 {
+		if u, ok := unitSizes[strings.ToLower(str[len(str)-2:])]; ok {
+			str, size = str[:len(str)-2], u
+		}
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+	if err != nil {
+		return 0, err
+	}
+	return f * size, nil
+}
+
+// Read several length attributes, missing attributes are 0
+func (s *SVG) nums(keys ...string) ([]float64, error) {
+	vals := make([]float64, len(keys))
+	for i, k := range keys {
+		var err error
+		if vals[i], err = s.num(k, 0); err != nil {
+			return nil, err
+		}
+	}
+	return vals, nil
+}
+
+// Find inherited attribute of s or its ancestors
+func (s *SVG) inherited(key string) (interface{}, bool) {
+	for g := s; g != nil; g = g.parent {
+		if v, ok := g.a[key]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// Find root of tree containing s
+func (s *SVG) root() *SVG {
+	g := s
+	for g.parent != nil {
+		g = g.parent
+	}
+	return g
+}
+
+// Find element referenced by href attribute of s
+func (s *SVG) href() *SVG {
+	v, ok := s.a["xlink:href"]
+	if !ok {
+		if v, ok = s.a["href"]; !ok {
+			return nil
+		}
+	}
+	id := strings.TrimPrefix(fmt.Sprint(v), "#")
+	return s.root().FindID(id)
+}
+
+// Read list of numbers like the points attribute
+func parseNumbers(str string) ([]float64, error) {
+	fields := strings.FieldsFunc(str, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+	vals := make([]float64, len(fields))
+	for i, f := range fields {
+		var err error
+		if vals[i], err = strconv.ParseFloat(f, 64); err != nil {
+			return nil, err
+		}
+	}
+	return vals, nil
+}
+
+// Read viewBox attribute. ok is false if s has no viewBox.
+func (s *SVG) viewBox() (vb [4]float64, ok bool, err error) {
+	v, ok := s.a["viewBox"]
+	if !ok {
+		return vb, false, nil
+	}
+	vals, err := parseNumbers(fmt.Sprint(v))
+	if err != nil || len(vals) != 4 {
+		return vb, false, errors.New("Could not read viewBox of <" + s.tag + ">: " + fmt.Sprint(v))
+	}
+	copy(vb[:], vals)
+	return vb, true, nil
+}
+
+// Transform mapping viewBox vb to viewport x, y, width, height as given by preserveAspectRatio.
+// Ref http://www.w3.org/TR/SVG11/coords.html#PreserveAspectRatioAttribute
+func viewBoxTransform(vb [4]float64, x, y, width, height float64, preserve string) Transform {
+	if vb[2] <= 0 || vb[3] <= 0 {
+		return Identity().Translate(x, y)
+	}
+	sx, sy := width/vb[2], height/vb[3]
+	fields := strings.Fields(strings.ToLower(preserve))
+	align := "xmidymid"
+	if len(fields) > 0 {
+		align = fields[0]
+	}
+	if align != "none" {
+		if len(fields) > 1 && fields[1] == "slice" {
+			sx = math.Max(sx, sy)
+		} else {
+			sx = math.Min(sx, sy)
+		}
+		sy = sx
+	}
+
+	// Placement of the scaled viewBox within the viewport
+	tx, ty := x-vb[0]*sx, y-vb[1]*sy
+	switch {
+	case strings.HasPrefix(align, "xmid"):
+		tx += (width - vb[2]*sx) / 2
+	case strings.HasPrefix(align, "xmax"):
+		tx += width - vb[2]*sx
+	}
+	switch {
+	case strings.HasSuffix(align, "ymid"):
+		ty += (height - vb[3]*sy) / 2
+	case strings.HasSuffix(align, "ymax"):
+		ty += height - vb[3]*sy
+	}
+	return Matrix(sx, 0, 0, sy, tx, ty)
+}
+
+// Transform from the viewport of s to the user space of its children, for nested svg and symbols.
+// width and height are used if s does not give its own.
+func (s *SVG) viewport(width, height float64) (Transform, error) {
+	vals, err := s.nums("x", "y")
+	if err != nil {
+		return Identity(), err
+	}
+	vb, ok, err := s.viewBox()
+	if err != nil || !ok {
+		return Identity().Translate(vals[0], vals[1]), err
+	}
+	if width == 0 {
+		width = vb[2]
+	}
+	if height == 0 {
+		height = vb[3]
+	}
+
+	// Percentages are relative to the given size, or else to the viewBox
+	if width, err = s.length("width", width, width); err != nil {
+		return Identity(), err
+	}
+	if height, err = s.length("height", height, height); err != nil {
+		return Identity(), err
+	}
+	preserve := "xMidYMid meet"
+	if v, ok := s.a["preserveAspectRatio"]; ok {
+		preserve = fmt.Sprint(v)
+	}
+	return viewBoxTransform(vb, vals[0], vals[1], width, height, preserve), nil
+}
+
+// Add extent of cubic curve, including its extreme points
+func (b *bounds) cubic(x0, y0, x1, y1, x2, y2, x3, y3 float64) {
+	b.add(x3, y3)
+	eval := func(p0, p1, p2, p3, t float64) float64 {
+		mt := 1 - t
+		return mt*mt*mt*p0 + 3*mt*mt*t*p1 + 3*mt*t*t*p2 + t*t*t*p3
+	}
+	// Roots of the derivative At^2 + Bt + C
+	roots := func(p0, p1, p2, p3 float64) []float64 {
+		a := p3 - 3*p2 + 3*p1 - p0
+		b := 2 * (p2 - 2*p1 + p0)
+		c := p1 - p0
+		if math.Abs(a) < 1e-12 {
+			if b == 0 {
+				return nil
+			}
+			return []float64{-c / b}
+		}
+		disc := b*b - 4*a*c
+		if disc < 0 {
+			return nil
+		}
+		sq := math.Sqrt(disc)
+		return []float64{(-b + sq) / (2 * a), (-b - sq) / (2 * a)}
+	}
+	for _, t := range append(roots(x0, x1, x2, x3), roots(y0, y1, y2, y3)...) {
+		if t > 0 && t < 1 {
+			b.add(eval(x0, x1, x2, x3, t), eval(y0, y1, y2, y3, t))
+		}
+	}
+}
+
+// Extent of s in its own user space, not including its transform. active holds groups referenced by use being measured.
+func (s *SVG) bounds(active map[*SVG]bool) (bounds, error) {
+	var b bounds
+	if v, ok := s.a["display"]; ok && fmt.Sprint(v) == "none" {
+		return b, nil
+	}
+
+	switch s.tag {
+	case "circle", "ellipse":
+		vals, err := s.nums("cx", "cy", "r", "rx", "ry")
+		if err != nil {
+			return b, err
+		}
+		rx, ry := vals[2], vals[2]
+		if s.tag == "ellipse" {
+			rx, ry = vals[3], vals[4]
+		}
+		b.add(vals[0]-rx, vals[1]-ry)
+		b.add(vals[0]+rx, vals[1]+ry)
+	case "rect", "image":
+		vals, err := s.nums("x", "y", "width", "height")
+		if err != nil {
+			return b, err
+		}
+		b.add(vals[0], vals[1])
+		b.add(vals[0]+vals[2], vals[1]+vals[3])
+	case "line":
+		vals, err := s.nums("x1", "y1", "x2", "y2")
+		if err != nil {
+			return b, err
+		}
+		b.add(vals[0], vals[1])
+		b.add(vals[2], vals[3])
+	case "polyline", "polygon":
+		vals, err := parseNumbers(fmt.Sprint(s.a["points"]))
+		if err != nil {
+			return b, errors.New("Could not read points of <" + s.tag + ">: " + err.Error())
+		}
+		for i := 0; i+1 < len(vals); i += 2 {
+			b.add(vals[i], vals[i+1])
+		}
+	case "path":
+		d, err := s.pathData()
+		if err != nil {
+			return b, err
+		}
+		var x, y float64
+		for _, c := range d.Normalize().Commands {
+			switch c.Command {
+			case 'M', 'L':
+				x, y = c.Args[0], c.Args[1]
+				b.add(x, y)
+			case 'C':
+				b.cubic(x, y, c.Args[0], c.Args[1], c.Args[2], c.Args[3], c.Args[4], c.Args[5])
+				x, y = c.Args[4], c.Args[5]
+			}
+		}
+	case "text":
+		vals, err := s.nums("x", "y")
+		if err != nil {
+			return b, err
+		}
+		// Estimate extent from font size, as glyph metrics are not known
+		size := float64(defaultFontSize)
+		if v, ok := s.inherited("font-size"); ok {
+			if size, err = strconv.ParseFloat(strings.TrimSuffix(fmt.Sprint(v), "px"), 64); err != nil {
+				return b, errors.New("Could not read font-size of <text>: " + fmt.Sprint(v))
+			}
+		}
+		width := 0.6 * size * float64(utf8.RuneCountInString(s.data))
+		x := vals[0]
+		if v, ok := s.inherited("text-anchor"); ok {
+			switch fmt.Sprint(v) {
+			case "middle":
+				x -= width / 2
+			case "end":
+				x -= width
+			}
+		}
+		b.add(x, vals[1]-0.8*size)
+		b.add(x+width, vals[1]+0.2*size)
+	case "use":
+		ref := s.href()
+		if ref == nil || active[ref] {
+			return b, nil
+		}
+		vals, err := s.nums("x", "y", "width", "height")
+		if err != nil {
+			return b, err
+		}
+		active[ref] = true
+		defer delete(active, ref)
+
+		t := Identity().Translate(vals[0], vals[1])
+		var c bounds
+		if ref.tag == "symbol" || ref.tag == "svg" {
+			vp, err := ref.viewport(vals[2], vals[3])
+			if err != nil {
+				return b, err
+			}
+			t = t.Multiply(vp)
+			c, err = ref.children(active)
+			if err != nil {
+				return b, err
+			}
+		} else {
+			rt, err := ref.Transform()
+			if err != nil {
+				return b, err
+			}
+			t = t.Multiply(rt)
+			if c, err = ref.bounds(active); err != nil {
+				return b, err
+			}
+		}
+		b.union(c, t)
+	case "svg":
+		vp, err := s.viewport(0, 0)
+		if err != nil {
+			return b, err
+		}
+		c, err := s.children(active)
+		if err != nil {
+			return b, err
+		}
+		b.union(c, vp)
+	default:
+		if notRendered[s.tag] {
+			return b, nil
+		}
+		return s.children(active)
+	}
+	return b, nil
+}
+
+// Extent of children of s in the user space of s
+func (s *SVG) children(active map[*SVG]bool) (bounds, error) {
+	var b bounds
+	for _, c := range s.mids {
+		if notRendered[c.tag] {
+			continue
+		}
+		cb, err := c.bounds(active)
+		if err != nil {
+			return b, err
+		}
+		t := Identity()
+		if c.tag != "svg" {
+			if t, err = c.Transform(); err != nil {
+				return b, err
+			}
+		}
+		b.union(cb, t)
+	}
+	return b, nil
+}
+
+// Compute bounding box of s and its children in the user space of s, not including the transform of s.
+// Stroke widths are not included and the extent of text is estimated from font size.
+func (s *SVG) BBox() (Box, error) {
+	var (
+		b   bounds
+		err error
+	)
+	// Children of svg are measured in the space of its viewBox
+	if s.tag == "svg" {
+		b, err = s.children(make(map[*SVG]bool))
+	} else {
+		b, err = s.bounds(make(map[*SVG]bool))
+	}
+	if err != nil {
+		return Box{}, err
+	}
+	if !b.ok {
+		return Box{}, errors.New("Found nothing drawn in <" + s.tag + ">")
+	}
+	return b.box(), nil
+}
+
+// Set viewBox of s to the bounding box of its content with margin added on all sides
+func (s *SVG) Fit(margin float64) error {
+	b, err := s.BBox()
+	if err != nil {
+		return err
+	}
+	s.a["viewBox"] = numberList{values: []float64{b.X - margin, b.Y - margin, b.Width + 2*margin, b.Height + 2*margin}}
+	return nil
+}
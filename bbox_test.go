@@ -0,0 +1,58 @@
+package smartSVG
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestBBox(t *testing.T) {
+	tests := []struct {
+		doc  string // Content of the root svg element
+		want Box
+	}{
+		{`<circle cx="10" cy="10" r="5" />`, Box{5, 5, 10, 10}},
+		{`<ellipse cx="10" cy="10" rx="5" ry="2" />`, Box{5, 8, 10, 4}},
+		{`<g transform="translate(100, 0)"><rect width="10" height="10" /></g>`, Box{100, 0, 10, 10}},
+		{`<g transform="scale(2)"><line x1="1" y1="2" x2="3" y2="-1" /></g>`, Box{2, -2, 4, 6}},
+		{`<polyline points="0,0 4,3 -2,1" />`, Box{-2, 0, 6, 3}},
+		{`<path d="M0 0A10 10 0 0 1 0 20" />`, Box{0, 0, 10, 20}},
+		{`<path d="M0 0C0 10 10 10 10 0" />`, Box{0, 0, 10, 7.5}},
+		{`<defs><rect id="r" x="-100" y="-100" width="1" height="1" /></defs><use xlink:href="#r" x="500" />`, Box{400, -100, 1, 1}},
+		{`<svg width="20" height="10" viewBox="0 0 2 1"><rect width="2" height="1" /></svg>`, Box{0, 0, 20, 10}},
+		{`<rect width="1" height="1" /><rect x="5" width="100" height="100" display="none" />`, Box{0, 0, 1, 1}},
+		{`<text x="10" y="20" font-size="10" text-anchor="end">ab</text>`, Box{-2, 12, 12, 10}},
+	}
+	for _, test := range tests {
+		s, err := Parse(strings.NewReader(`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">` + test.doc + `</svg>`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.BBox()
+		switch {
+		case err != nil:
+			t.Errorf("BBox of %s gave error %v", test.doc, err)
+		case !closeBoxes(got, test.want):
+			t.Errorf("BBox of %s = %v, want %v", test.doc, got, test.want)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	s := New(100, 100)
+	if err := s.Fit(1); err == nil {
+		t.Error("Fit of empty document gave no error")
+	}
+	s.Circle(10, 10, 5, nil)
+	if err := s.Fit(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.a["viewBox"].(numberList).String(); got != "4 4 12 12" {
+		t.Errorf("viewBox after Fit is %s, want 4 4 12 12", got)
+	}
+}
+
+func closeBoxes(a, b Box) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9 &&
+		math.Abs(a.Width-b.Width) < 1e-9 && math.Abs(a.Height-b.Height) < 1e-9
+}
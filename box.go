@@ -0,0 +1,175 @@
+package smartSVG
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Rules placing the whiskers of box plots
+type Whiskers int
+
+const (
+	Tukey  Whiskers = iota // Whiskers reach the furthest samples within 1.5 IQR of the box. Samples beyond are outliers.
+	MinMax                 // Whiskers reach the smallest and largest samples
+)
+
+// Options of BoxPlot and ViolinPlot. The zero value gives Tukey whiskers and a bandwidth by Silverman's rule.
+type BoxOptions struct {
+	Title     string
+	Whiskers  Whiskers
+	Width     float64 // Part of each group covered by its box or violin, between 0 and 1. 0 gives 0.6.
+	Bandwidth float64 // Bandwidth of the kernel density estimate of violins. 0 chooses it by Silverman's rule.
+}
+
+// Quartiles, whiskers and outliers of a group of samples
+type boxStats struct {
+	q1, median, q3 float64
+	lo, hi         float64 // Ends of whiskers
+	outliers       []float64
+}
+
+// Summarize sorted samples, with whiskers placed by rule
+func summarize(sorted []float64, rule Whiskers) boxStats {
+	st := boxStats{q1: quantile(sorted, 0.25), median: quantile(sorted, 0.5), q3: quantile(sorted, 0.75)}
+	st.lo, st.hi = sorted[0], sorted[len(sorted)-1]
+	if rule == MinMax {
+		return st
+	}
+	fence := 1.5 * (st.q3 - st.q1)
+	st.lo, st.hi = st.q1, st.q3
+	for _, v := range sorted {
+		switch {
+		case v < st.q1-fence || v > st.q3+fence:
+			st.outliers = append(st.outliers, v)
+		case v < st.lo:
+			st.lo = v
+		case v > st.hi:
+			st.hi = v
+		}
+	}
+	return st
+}
+
+// Draw one box or violin per group side by side, labelled below. draw is called for each group in its series,
+// with the centre and half width of its slot and the sorted samples.
+func (s *SVG) distributionChart(x, y, width, height int, groups [][]float64, labels []string, opts BoxOptions,
+	draw func(c chart, sc scale, g *SVG, centre, half float64, samples []float64, st boxStats)) (*SVG, error) {
+	switch {
+	case len(groups) == 0:
+		return nil, errors.New("Got no groups")
+	case len(labels) != len(groups):
+		return nil, errors.New("Got " + fmt.Sprint(len(labels)) + " labels for " + fmt.Sprint(len(groups)) + " groups")
+	case opts.Whiskers != Tukey && opts.Whiskers != MinMax:
+		return nil, errors.New("Got unknown whisker rule")
+	case opts.Width < 0 || opts.Width > 1:
+		return nil, errors.New("Width " + fmt.Sprint(opts.Width) + " is not between 0 and 1")
+	case opts.Bandwidth < 0:
+		return nil, errors.New("Bandwidth must not be negative")
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for i, group := range groups {
+		if len(group) == 0 {
+			return nil, errors.New("Group " + labels[i] + " has no samples")
+		}
+		for _, v := range group {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return nil, errors.New("Sample " + fmt.Sprint(v) + " of group " + labels[i] + " is not a number")
+			}
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+	boxWidth := opts.Width
+	if boxWidth == 0 {
+		boxWidth = 0.6
+	}
+
+	c, err := s.newChart(x, y, width, height, opts.Title)
+	if err != nil {
+		return nil, err
+	}
+	sc := niceScale(lo, hi, c.height)
+	c.valueAxis(sc, false)
+	c.categoryAxis(labels, false)
+
+	slot := c.slot(len(groups), false)
+	for i, group := range groups {
+		samples := sorted(group)
+		st := summarize(samples, opts.Whiskers)
+		g := c.series()
+		g.a["stroke"] = "black"
+		g.Title(labels[i] + ": median " + exactFormat.Format(st.median) + ", quartiles " +
+			exactFormat.Format(st.q1) + " to " + exactFormat.Format(st.q3))
+		draw(c, sc, g, (float64(i)+0.5)*slot, slot*boxWidth/2, samples, st)
+	}
+	c.frame()
+	return c.top, nil
+}
+
+// Draw box plot of each group of samples, with whiskers and outliers. Labels name the groups.
+// The chart works with Legend and SeriesFill like Diagram.
+func (s *SVG) BoxPlot(x, y, width, height int, groups [][]float64, labels []string, opts BoxOptions) (*SVG, error) {
+	return s.distributionChart(x, y, width, height, groups, labels, opts,
+		func(c chart, sc scale, g *SVG, centre, half float64, samples []float64, st boxStats) {
+			pos := func(v float64) float64 { return c.valuePos(sc, v, false) }
+			g.LineF(centre, pos(st.q3), centre, pos(st.hi), nil)
+			g.LineF(centre, pos(st.q1), centre, pos(st.lo), nil)
+			g.LineF(centre-half/2, pos(st.hi), centre+half/2, pos(st.hi), nil)
+			g.LineF(centre-half/2, pos(st.lo), centre+half/2, pos(st.lo), nil)
+			g.RectF(centre-half, pos(st.q3), 2*half, pos(st.q1)-pos(st.q3), nil)
+			g.LineF(centre-half, pos(st.median), centre+half, pos(st.median), Att{"stroke-width": "2"})
+			for _, v := range st.outliers {
+				g.CircleF(centre, pos(v), 2.5, Att{"fill": "none"})
+			}
+		})
+}
+
+// Draw violin plot of each group of samples, showing its kernel density estimate between its smallest and largest
+// sample, with quartiles and whiskers inside. Labels name the groups.
+// The chart works with Legend and SeriesFill like Diagram.
+func (s *SVG) ViolinPlot(x, y, width, height int, groups [][]float64, labels []string, opts BoxOptions) (*SVG, error) {
+	return s.distributionChart(x, y, width, height, groups, labels, opts,
+		func(c chart, sc scale, g *SVG, centre, half float64, samples []float64, st boxStats) {
+			pos := func(v float64) float64 { return c.valuePos(sc, v, false) }
+
+			// Silverman's rule of thumb
+			bandwidth := opts.Bandwidth
+			if bandwidth == 0 {
+				spread := math.Min(stdDev(samples), (st.q3-st.q1)/1.34)
+				if spread == 0 {
+					spread = stdDev(samples)
+				}
+				bandwidth = 0.9 * spread * math.Pow(float64(len(samples)), -0.2)
+			}
+			if bandwidth == 0 {
+				bandwidth = 1
+			}
+
+			// Gaussian kernel density, evaluated at steps from the smallest to the largest sample
+			const steps = 64
+			lo, hi := samples[0], samples[len(samples)-1]
+			vals, density := make([]float64, steps+1), make([]float64, steps+1)
+			for i := range vals {
+				vals[i] = lo + (hi-lo)*float64(i)/steps
+				for _, v := range samples {
+					u := (vals[i] - v) / bandwidth
+					density[i] += math.Exp(-u * u / 2)
+				}
+			}
+			largest := max(density...)
+
+			d := NewPathData().MoveTo(centre+half*density[0]/largest, pos(vals[0]))
+			for i := 1; i <= steps; i++ {
+				d.LineTo(centre+half*density[i]/largest, pos(vals[i]))
+			}
+			for i := steps; i >= 0; i-- {
+				d.LineTo(centre-half*density[i]/largest, pos(vals[i]))
+			}
+			g.Path(d.Close(), nil)
+
+			inner := g.G(Att{"fill": "black"})
+			inner.LineF(centre, pos(st.lo), centre, pos(st.hi), nil)
+			inner.RectF(centre-half/10, pos(st.q3), half/5, pos(st.q1)-pos(st.q3), nil)
+			inner.CircleF(centre, pos(st.median), 2.5, Att{"fill": "white", "stroke": "none"})
+		})
+}
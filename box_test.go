@@ -0,0 +1,105 @@
+package smartSVG
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		vals []float64
+		rule Whiskers
+		want boxStats
+	}{
+		{[]float64{1, 2, 3, 4, 5, 6, 7, 8, 100}, Tukey, boxStats{3, 5, 7, 1, 8, []float64{100}}},
+		{[]float64{1, 2, 3, 4, 5, 6, 7, 8, 100}, MinMax, boxStats{3, 5, 7, 1, 100, nil}},
+		{[]float64{-50, 10, 11, 12, 13}, Tukey, boxStats{10, 11, 12, 10, 13, []float64{-50}}},
+		{[]float64{2, 2, 2}, Tukey, boxStats{2, 2, 2, 2, 2, nil}},
+	}
+	for _, test := range tests {
+		if got := summarize(test.vals, test.rule); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("summarize(%v, %v) = %+v, want %+v", test.vals, test.rule, got, test.want)
+		}
+	}
+}
+
+func TestBoxPlotErrors(t *testing.T) {
+	tests := []struct {
+		groups [][]float64
+		labels []string
+		opts   BoxOptions
+	}{
+		{nil, nil, BoxOptions{}},
+		{[][]float64{{1}}, []string{"a", "b"}, BoxOptions{}},
+		{[][]float64{{}}, []string{"a"}, BoxOptions{}},
+		{[][]float64{{1, math.NaN()}}, []string{"a"}, BoxOptions{}},
+		{[][]float64{{1}}, []string{"a"}, BoxOptions{Whiskers: 2}},
+		{[][]float64{{1}}, []string{"a"}, BoxOptions{Width: 1.5}},
+		{[][]float64{{1}}, []string{"a"}, BoxOptions{Bandwidth: -1}},
+	}
+	for _, test := range tests {
+		if _, err := New(400, 300).BoxPlot(0, 0, 400, 300, test.groups, test.labels, test.opts); err == nil {
+			t.Errorf("BoxPlot of %v with labels %q and options %+v gave no error", test.groups, test.labels, test.opts)
+		}
+		if _, err := New(400, 300).ViolinPlot(0, 0, 400, 300, test.groups, test.labels, test.opts); err == nil {
+			t.Errorf("ViolinPlot of %v with labels %q and options %+v gave no error", test.groups, test.labels, test.opts)
+		}
+	}
+}
+
+func TestBoxAndViolinPlot(t *testing.T) {
+	groups := [][]float64{{1, 2, 3, 4, 5, 6, 7, 8, 100}, {4, 5, 5, 6}, {3, 3, 3}}
+	labels := []string{"a", "b", "c"}
+	for _, plot := range []func(*SVG) (*SVG, error){
+		func(s *SVG) (*SVG, error) { return s.BoxPlot(0, 0, 400, 300, groups, labels, BoxOptions{Title: "Box"}) },
+		func(s *SVG) (*SVG, error) {
+			return s.ViolinPlot(0, 0, 400, 300, groups, labels, BoxOptions{Title: "Violin"})
+		},
+	} {
+		s := New(800, 300)
+		d, err := plot(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := plot(s.G(Translate(400, 0))); err != nil {
+			t.Fatal(err)
+		}
+		if n := len(d.series()); n != len(groups) {
+			t.Fatalf("Chart has %d series, want %d", n, len(groups))
+		}
+		if err := d.SeriesFill("red", "green", "blue"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := d.Legend(labels...); err != nil {
+			t.Fatal(err)
+		}
+		got := s.String()
+		for _, want := range []string{`<title>a: median 5, quartiles 3 to 7</title>`, `<title>c: median 3, quartiles 3 to 3</title>`, `fill="blue"`} {
+			if !strings.Contains(got, want) {
+				t.Errorf("Chart has no %s:\n%s", want, got)
+			}
+		}
+		if issues := s.Validate(); len(issues) != 0 {
+			t.Errorf("Document with two charts has issues %v", issues)
+		}
+	}
+}
+
+func TestBoxPlotOutliers(t *testing.T) {
+	d, err := New(400, 300).BoxPlot(0, 0, 400, 300, [][]float64{{1, 2, 3, 4, 5, 6, 7, 8, 100}}, []string{"a"}, BoxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if circles := d.series()[0].FindGroups("circle"); len(circles) != 1 {
+		t.Errorf("Box has %d outliers, want 1", len(circles))
+	}
+	d, err = New(400, 300).BoxPlot(0, 0, 400, 300, [][]float64{{1, 2, 3, 4, 5, 6, 7, 8, 100}}, []string{"a"}, BoxOptions{Whiskers: MinMax})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if circles := d.series()[0].FindGroups("circle"); len(circles) != 0 {
+		t.Errorf("Box with whiskers to the extremes has %d outliers, want none", len(circles))
+	}
+}
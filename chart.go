@@ -0,0 +1,235 @@
+package smartSVG
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Layout of charts in pixels
+const (
+	chartTitleHeight = 25
+	chartTextHeight  = 10
+	chartTextRoomX   = 70 // Room for labels left of the plot area
+	chartTextRoomY   = 30 // Room for labels below the plot area
+	chartMargin      = 15 // Room right of the plot area, for labels centred on its edge
+	chartTicks       = 5  // Wanted number of steps between ticks of value axes
+)
+
+// Diagram group with an empty plot area, shared by the charts
+type chart struct {
+	top           *SVG    // Group with id diagram, as used by Legend and SeriesFill
+	plot          *SVG    // Group with origin in the upper left corner of the plot area
+	width, height float64 // Size of plot area
+}
+
+// Start diagram at x, y with title above the plot area
+func (s *SVG) newChart(x, y, width, height int, title string) (chart, error) {
+	if width <= chartTextRoomX+chartMargin || height <= chartTitleHeight+chartTextRoomY {
+		return chart{}, errors.New("Chart of size " + fmt.Sprint(width, "x", height) + " has no room for plot")
+	}
+	top := s.GID("diagram"+s.newDiagramSuffix(), Att{"width": width, "height": height})
+	top.startColours()
+	top.AddAtt(false, Translate(float64(x), float64(y)))
+
+	// Draw background in order to make whole object clickable
+	top.Rect(0, 0, width, height, Att{"fill": "white"})
+	top.Text(width/2, 3*chartTitleHeight/4, title, Att{"text-anchor": "middle", "fill": "black", "id": "title" + top.idSuffix()})
+
+	plot := top.GID("plot"+top.idSuffix(), Translate(chartTextRoomX, chartTitleHeight))
+	return chart{top, plot, float64(width - chartTextRoomX - chartMargin), float64(height - chartTitleHeight - chartTextRoomY)}, nil
+}
+
+// Parts of diagrams, which have ids like plot for the first diagram of a document and plot-2 for the second
+var diagramParts = []string{"diagram", "title", "plot", "data", "legend"}
+
+// Suffix of the ids of a new diagram, which no element of the document of s has with any of the parts
+func (s *SVG) newDiagramSuffix() string {
+	root := s.root()
+	for i := 1; ; i++ {
+		suffix := ""
+		if i > 1 {
+			suffix = "-" + fmt.Sprint(i)
+		}
+		free := true
+		for _, part := range diagramParts {
+			free = free && root.FindID(part+suffix) == nil
+		}
+		if free {
+			return suffix
+		}
+	}
+}
+
+// Whether s is the group of a diagram or chart, with id diagram or diagram-n
+func (s *SVG) isDiagram() bool {
+	id, ok := s.a["id"].(string)
+	if !ok || !strings.HasPrefix(id, "diagram") {
+		return false
+	}
+	suffix := s.idSuffix()
+	n, err := strconv.Atoi(strings.TrimPrefix(suffix, "-"))
+	return suffix == "" || strings.HasPrefix(suffix, "-") && err == nil && n > 1
+}
+
+// Suffix of the id of diagram s, which its parts share
+func (s *SVG) idSuffix() string {
+	return strings.TrimPrefix(fmt.Sprint(s.a["id"]), "diagram")
+}
+
+// Part of diagram s, like plot or data
+func (s *SVG) part(name string) *SVG {
+	return s.FindID(name + s.idSuffix())
+}
+
+// Draw frame around plot area, after its content
+func (c chart) frame() {
+	c.plot.RectF(0, 0, c.width, c.height, Att{"stroke": "grey", "stroke-width": "1", "fill": "none"})
+}
+
+// Linear mapping of values from lo to hi onto 0 to length, with ticks every step
+type scale struct {
+	lo, hi, step, length float64
+}
+
+// Scale covering lo to hi, widened to whole ticks of a step of 1, 2 or 5 times a power of ten
+func niceScale(lo, hi, length float64) scale {
+	if lo == hi {
+		lo, hi = lo-1, hi+1
+	}
+	step := niceStep((hi - lo) / chartTicks)
+	return scale{math.Floor(lo/step) * step, math.Ceil(hi/step) * step, step, length}
+}
+
+// Smallest step of 1, 2 or 5 times a power of ten which is at least raw
+func niceStep(raw float64) float64 {
+	step := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*step >= raw {
+			return m * step
+		}
+	}
+	return 10 * step
+}
+
+// Position of v along the scale
+func (sc scale) pos(v float64) float64 {
+	return (v - sc.lo) / (sc.hi - sc.lo) * sc.length
+}
+
+// Values of the ticks from lo to hi
+func (sc scale) ticks() []float64 {
+	n := int(math.Round((sc.hi - sc.lo) / sc.step))
+	ticks := make([]float64, n+1)
+	for i := range ticks {
+		ticks[i] = sc.lo + float64(i)*sc.step
+	}
+	return ticks
+}
+
+// Number of decimals which ticks of step need
+func tickDecimals(step float64) int {
+	return int(math.Max(0, -math.Floor(math.Log10(step)+1e-9)))
+}
+
+// Label of tick v, with as many decimals as the step needs
+func tickLabel(v, step float64) string {
+	return Decimals(tickDecimals(step)).Format(v)
+}
+
+// Position across the plot area of value v. Values grow upwards along vertical axes.
+func (c chart) valuePos(sc scale, v float64, horizontal bool) float64 {
+	if horizontal {
+		return sc.pos(v)
+	}
+	return c.height - sc.pos(v)
+}
+
+// Draw grid lines and labels of value axis with scale sc, along the bottom if horizontal and else along the left side
+func (c chart) valueAxis(sc scale, horizontal bool) {
+	g := c.plot.G(Att{"class": "axis"})
+	grid := g.G(Att{"stroke": "lightgrey", "stroke-width": "1"})
+	labels := g.G(Att{"fill": "black"})
+	for _, t := range sc.ticks() {
+		p := c.valuePos(sc, t, horizontal)
+		if horizontal {
+			grid.LineF(p, 0, p, c.height, nil)
+			labels.TextF(p, c.height+chartTextHeight+2, tickLabel(t, sc.step), Att{"text-anchor": "middle"})
+		} else {
+			grid.LineF(0, p, c.width, p, nil)
+			labels.TextF(-5, p+chartTextHeight/3, tickLabel(t, sc.step), Att{"text-anchor": "end"})
+		}
+	}
+}
+
+// Width of the slot of each of n categories along the category axis
+func (c chart) slot(n int, horizontal bool) float64 {
+	if horizontal {
+		return c.height / float64(n)
+	}
+	return c.width / float64(n)
+}
+
+// Draw labels of categories centred on their slots, along the left side if horizontal and else along the bottom
+func (c chart) categoryAxis(labels []string, horizontal bool) {
+	g := c.plot.G(Att{"class": "axis", "fill": "black"})
+	slot := c.slot(len(labels), horizontal)
+	for i, l := range labels {
+		p := (float64(i) + 0.5) * slot
+		if horizontal {
+			g.TextF(-5, p+chartTextHeight/3, l, Att{"text-anchor": "end"})
+		} else {
+			g.TextF(p, c.height+chartTextHeight+2, l, Att{"text-anchor": "middle"})
+		}
+	}
+}
+
+// Draw line across the plot area at value v, such as the zero baseline
+func (c chart) baseline(sc scale, v float64, horizontal bool) {
+	p := c.valuePos(sc, v, horizontal)
+	a := Att{"stroke": "black", "stroke-width": "1"}
+	if horizontal {
+		c.plot.LineF(p, 0, p, c.height, a)
+	} else {
+		c.plot.LineF(0, p, c.width, p, a)
+	}
+}
+
+// Start group of series drawn with the next colour of the diagram, found by Legend and SeriesFill
+func (c chart) series() *SVG {
+	return c.top.addSeries(c.plot.G(Att{"class": "series", "fill": c.top.NextColour().String()}))
+}
+
+// Record g as the next series of diagram s. Diagrams read by Parse start from the series they already draw.
+func (s *SVG) addSeries(g *SVG) *SVG {
+	if s.added == nil {
+		for _, c := range s.drawnSeries() {
+			if c != g {
+				s.added = append(s.added, c)
+			}
+		}
+	}
+	s.added = append(s.added, g)
+	return g
+}
+
+// Series of diagram in the order they were added. Diagrams read by Parse give them in the order they are drawn.
+func (s *SVG) series() []*SVG {
+	if s.added != nil {
+		return s.added
+	}
+	return s.drawnSeries()
+}
+
+// Series in the order they are drawn: groups of markers and bars, and polylines of plots
+func (s *SVG) drawnSeries() (series []*SVG) {
+	if s.tag == "polyline" || s.a["class"] == "series" {
+		return []*SVG{s}
+	}
+	for _, c := range s.mids {
+		series = append(series, c.drawnSeries()...)
+	}
+	return
+}
@@ -0,0 +1,86 @@
+package smartSVG
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSeriesOrder(t *testing.T) {
+	s := New(600, 400)
+	d, err := s.Diagram(0, 0, 600, 400, Data{[]float64{0, 1, 2}, []float64{1, 3, 2}}, "Order", Scatter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line, err := d.AddPlot(Data{[]float64{0, 1, 2}, []float64{2, 1, 3}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	series := d.series()
+	if len(series) != 2 || series[0].tag != "g" || series[1] != line {
+		t.Fatalf("Series are %v, want the scatter group and then the line", series)
+	}
+}
+
+func TestSeriesOfParsedDiagram(t *testing.T) {
+	s := New(600, 400)
+	d, err := s.Diagram(0, 0, 600, 400, Data{[]float64{0, 1, 2}, []float64{1, 3, 2}}, "Parsed", Continuous)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.AddScatter(Data{[]float64{0, 2}, []float64{0, 1}}, ScatterOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := s.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Parse(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := p.FindID("diagram")
+	line, err := parsed.AddPlot(Data{[]float64{0, 1, 2}, []float64{2, 1, 3}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series := parsed.series(); len(series) != 3 || series[2] != line {
+		t.Fatalf("Series are %v, want the two parsed series and then the added line", series)
+	}
+	if _, err := parsed.Legend("a", "b", "c"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestIDsOfTwoDiagrams(t *testing.T) {
+	s := New(600, 800)
+	var markers []*SVG
+	for i, y := range []int{0, 400} {
+		d, err := s.Diagram(0, y, 600, 400, Data{[]float64{0, 1, 2}, []float64{1, 3, 2}}, "Columns", Column)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := d.AddPlot(Data{[]float64{0, 1, 2}, []float64{2, 1, 3}}, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := d.Legend("first", "second"); err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"diagram", "diagram-2"}[i]; d.a["id"] != want {
+			t.Errorf("Diagram %d has id %v, want %s", i+1, d.a["id"], want)
+		}
+		for _, series := range d.series() {
+			markers = append(markers, d.seriesMarker(series))
+		}
+	}
+	seen := map[*SVG]bool{nil: true}
+	for i, m := range markers {
+		if seen[m] {
+			t.Errorf("Series %d has no marker of its own", i+1)
+		}
+		seen[m] = true
+	}
+	if issues := s.Validate(); len(issues) != 0 {
+		t.Errorf("Document with two diagrams has issues %v", issues)
+	}
+}
@@ -0,0 +1,27 @@
+package smartSVG
+
+// Create clipping path. Children of the group make up the clipping region. Should be placed inside defs.
+// Ref http://www.w3.org/TR/SVG11/masking.html#ClipPathElement
+func (s *SVG) ClipPath(id string, a Att) *SVG {
+	g := s.newGroup("clipPath", a)
+	g.a["id"] = id
+	return g
+}
+
+// Create mask. Luminance of children of the group is used as mask. Should be placed inside defs.
+// Ref http://www.w3.org/TR/SVG11/masking.html#MaskElement
+func (s *SVG) Mask(id string, a Att) *SVG {
+	g := s.newGroup("mask", a)
+	g.a["id"] = id
+	return g
+}
+
+// Clip group to clipping path with given id
+func (s *SVG) SetClipPath(id string) {
+	s.a["clip-path"] = URL(id)
+}
+
+// Mask group with mask of given id
+func (s *SVG) SetMask(id string) {
+	s.a["mask"] = URL(id)
+}
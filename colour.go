@@ -0,0 +1,260 @@
+package smartSVG
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Colour in sRGB with alpha. Components are between 0 and 1, and not premultiplied.
+// Colour implements fmt.Stringer, so it can be used directly as value in Att.
+type Colour struct {
+	R, G, B, A float64
+}
+
+// Colour from 8 bit components and alpha between 0 and 1
+func RGBA(r, g, b uint8, a float64) Colour {
+	return Colour{float64(r) / 255, float64(g) / 255, float64(b) / 255, clamp(a)}
+}
+
+// Colour from hue in degrees, saturation and lightness between 0 and 1
+func HSL(h, s, l float64) Colour {
+	s, l = clamp(s), clamp(l)
+	c := (1 - math.Abs(2*l-1)) * s
+	return hueColour(h, c, l-c/2)
+}
+
+// Colour from hue in degrees, saturation and value between 0 and 1
+func HSV(h, s, v float64) Colour {
+	s, v = clamp(s), clamp(v)
+	c := v * s
+	return hueColour(h, c, v-c)
+}
+
+// Opaque colour with hue h in degrees, chroma c and offset m of all components
+func hueColour(h, c, m float64) Colour {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g = c, x
+	case h < 120:
+		r, g = x, c
+	case h < 180:
+		g, b = c, x
+	case h < 240:
+		g, b = x, c
+	case h < 300:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	return Colour{r + m, g + m, b + m, 1}
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// Parse colour given as name, #rgb, #rgba, #rrggbb, #rrggbbaa, rgb(), rgba(), hsl() or hsla().
+// Arguments of functions may be separated by commas or spaces, with alpha after a slash.
+func ParseColour(str string) (Colour, error) {
+	str = strings.ToLower(strings.TrimSpace(str))
+	if c, ok := namedColours[str]; ok {
+		return RGBA(c.R, c.G, c.B, 1), nil
+	}
+	bad := errors.New("Could not parse colour " + str)
+	switch {
+	case str == "transparent":
+		return Colour{}, nil
+	case strings.HasPrefix(str, "#"):
+		hex := str[1:]
+		if len(hex) == 3 || len(hex) == 4 {
+			long := ""
+			for _, c := range hex {
+				long += string(c) + string(c)
+			}
+			hex = long
+		}
+		if len(hex) != 6 && len(hex) != 8 {
+			return Colour{}, bad
+		}
+		v := []float64{1, 1, 1, 1}
+		for i := 0; i < len(hex); i += 2 {
+			n, err := strconv.ParseUint(hex[i:i+2], 16, 8)
+			if err != nil {
+				return Colour{}, bad
+			}
+			v[i/2] = float64(n) / 255
+		}
+		return Colour{v[0], v[1], v[2], v[3]}, nil
+	case !strings.HasSuffix(str, ")") || !strings.Contains(str, "("):
+		return Colour{}, bad
+	}
+
+	fn := str[:strings.Index(str, "(")]
+	args := strings.FieldsFunc(str[len(fn)+1:len(str)-1], func(r rune) bool {
+		return r == ',' || r == ' ' || r == '/'
+	})
+	if len(args) != 3 && len(args) != 4 {
+		return Colour{}, bad
+	}
+	// Read argument i, where percentages are relative to 100 and plain numbers to scale
+	v := make([]float64, 4)
+	v[3] = 1
+	for i, arg := range args {
+		scale := 1.0
+		switch {
+		case i == 3:
+		case fn == "rgb" || fn == "rgba":
+			scale = 255
+		case i == 0:
+			arg = strings.TrimSuffix(arg, "deg")
+		}
+		if strings.HasSuffix(arg, "%") {
+			arg, scale = arg[:len(arg)-1], 100
+		}
+		f, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return Colour{}, bad
+		}
+		v[i] = f / scale
+	}
+	switch fn {
+	case "rgb", "rgba":
+		return Colour{clamp(v[0]), clamp(v[1]), clamp(v[2]), clamp(v[3])}, nil
+	case "hsl", "hsla":
+		// Saturation and lightness are percentages, as plain numbers up to 100
+		if !strings.HasSuffix(args[1], "%") {
+			v[1] /= 100
+		}
+		if !strings.HasSuffix(args[2], "%") {
+			v[2] /= 100
+		}
+		return HSL(v[0], v[1], v[2]).WithAlpha(v[3]), nil
+	}
+	return Colour{}, bad
+}
+
+// Hue in degrees, saturation and lightness between 0 and 1
+func (c Colour) HSL() (h, s, l float64) {
+	max, min := math.Max(c.R, math.Max(c.G, c.B)), math.Min(c.R, math.Min(c.G, c.B))
+	l = (max + min) / 2
+	if d := max - min; d > 0 {
+		s = d / (1 - math.Abs(2*l-1))
+	}
+	return c.hue(), s, l
+}
+
+// Hue in degrees, saturation and value between 0 and 1
+func (c Colour) HSV() (h, s, v float64) {
+	max, min := math.Max(c.R, math.Max(c.G, c.B)), math.Min(c.R, math.Min(c.G, c.B))
+	if max > 0 {
+		s = (max - min) / max
+	}
+	return c.hue(), s, max
+}
+
+// Hue in degrees, 0 for grey
+func (c Colour) hue() float64 {
+	max, min := math.Max(c.R, math.Max(c.G, c.B)), math.Min(c.R, math.Min(c.G, c.B))
+	d := max - min
+	var h float64
+	switch {
+	case d == 0:
+		return 0
+	case max == c.R:
+		h = math.Mod((c.G-c.B)/d, 6)
+	case max == c.G:
+		h = (c.B-c.R)/d + 2
+	default:
+		h = (c.R-c.G)/d + 4
+	}
+	if h *= 60; h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// Linear light of sRGB component
+func linear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// CIE L*a*b* under illuminant D65, with lightness between 0 and 100
+func (c Colour) Lab() (l, a, b float64) {
+	r, g, bl := linear(c.R), linear(c.G), linear(c.B)
+	x := (0.4124564*r + 0.3575761*g + 0.1804375*bl) / 0.95047
+	y := 0.2126729*r + 0.7151522*g + 0.0721750*bl
+	z := (0.0193339*r + 0.1191920*g + 0.9503041*bl) / 1.08883
+	f := func(t float64) float64 {
+		if t > 216.0/24389 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27*t + 16) / 116
+	}
+	fx, fy, fz := f(x), f(y), f(z)
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
+
+// Colour with lightness increased by amount between 0 and 1
+func (c Colour) Lighten(amount float64) Colour {
+	h, s, l := c.HSL()
+	return HSL(h, s, l+amount).WithAlpha(c.A)
+}
+
+// Colour with lightness decreased by amount between 0 and 1
+func (c Colour) Darken(amount float64) Colour {
+	return c.Lighten(-amount)
+}
+
+// Mix c with other, where weight is the part of other between 0 and 1
+func (c Colour) Mix(other Colour, weight float64) Colour {
+	w := clamp(weight)
+	mix := func(a, b float64) float64 { return a + w*(b-a) }
+	return Colour{mix(c.R, other.R), mix(c.G, other.G), mix(c.B, other.B), mix(c.A, other.A)}
+}
+
+func (c Colour) WithAlpha(a float64) Colour {
+	c.A = clamp(a)
+	return c
+}
+
+// Relative luminance as defined by WCAG 2. Ref https://www.w3.org/TR/WCAG20/#relativeluminancedef
+func (c Colour) Luminance() float64 {
+	return 0.2126*linear(c.R) + 0.7152*linear(c.G) + 0.0722*linear(c.B)
+}
+
+// WCAG contrast ratio between c and other, from 1 to 21. Alpha is ignored.
+func (c Colour) Contrast(other Colour) float64 {
+	l1, l2 := c.Luminance(), other.Luminance()
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// RGBA implements image/color.Color
+func (c Colour) RGBA() (r, g, b, a uint32) {
+	a = uint32(clamp(c.A)*0xffff + 0.5)
+	return uint32(clamp(c.R)*float64(a) + 0.5), uint32(clamp(c.G)*float64(a) + 0.5), uint32(clamp(c.B)*float64(a) + 0.5), a
+}
+
+// Format as #rrggbb, or as rgba() if not opaque
+func (c Colour) String() string {
+	r, g, b := byte(clamp(c.R)*255+0.5), byte(clamp(c.G)*255+0.5), byte(clamp(c.B)*255+0.5)
+	if c.A >= 1 {
+		return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	}
+	return "rgba(" + strconv.Itoa(int(r)) + ", " + strconv.Itoa(int(g)) + ", " + strconv.Itoa(int(b)) + ", " +
+		strconv.FormatFloat(math.Round(clamp(c.A)*1000)/1000, 'f', -1, 64) + ")"
+}
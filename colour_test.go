@@ -0,0 +1,44 @@
+package smartSVG
+
+import "testing"
+
+func TestParseColour(t *testing.T) {
+	tests := []struct {
+		str, want string // want is empty if str is invalid
+	}{
+		{"red", "#ff0000"},
+		{" SteelBlue ", "#4682b4"},
+		{"transparent", "rgba(0, 0, 0, 0)"},
+		{"#abc", "#aabbcc"},
+		{"#abc8", "rgba(170, 187, 204, 0.533)"},
+		{"#0080ff", "#0080ff"},
+		{"#0080ff80", "rgba(0, 128, 255, 0.502)"},
+		{"rgb(255, 128, 0)", "#ff8000"},
+		{"rgb(100%, 50%, 0%)", "#ff8000"},
+		{"rgb(255 128 0 / 0.5)", "rgba(255, 128, 0, 0.5)"},
+		{"rgba(0, 0, 255, 25%)", "rgba(0, 0, 255, 0.25)"},
+		{"hsl(0, 100%, 50%)", "#ff0000"},
+		{"hsl(120deg 100% 25%)", "#008000"},
+		{"hsla(240, 100%, 50%, 0.5)", "rgba(0, 0, 255, 0.5)"},
+		{"rgb(300, -20, 0)", "#ff0000"},
+		{"nocolour", ""},
+		{"#12", ""},
+		{"#ggg", ""},
+		{"rgb(1, 2)", ""},
+		{"rgb(1, 2, x)", ""},
+		{"rgb(1, 2, 3", ""},
+	}
+	for _, test := range tests {
+		c, err := ParseColour(test.str)
+		switch {
+		case test.want == "":
+			if err == nil {
+				t.Errorf("ParseColour(%q) = %v, want error", test.str, c)
+			}
+		case err != nil:
+			t.Errorf("ParseColour(%q) gave error %v", test.str, err)
+		case c.String() != test.want:
+			t.Errorf("ParseColour(%q) = %v, want %v", test.str, c, test.want)
+		}
+	}
+}
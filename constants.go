@@ -2,8 +2,10 @@ package smartSVG
 
 import (
 	"container/ring"
+	"image/color"
 	"math/rand"
 	"strconv"
+	"sync"
 )
 
 const (
@@ -156,7 +158,162 @@ const (
 	Yellowgreen          = "yellowgreen"          // rgb(154, 205, 50)
 )
 
-var c *ring.Ring
+// RGB values of the named colours
+var namedColours = map[string]color.RGBA{
+	Aliceblue:            {240, 248, 255, 255},
+	Antiquewhite:         {250, 235, 215, 255},
+	Aqua:                 {0, 255, 255, 255},
+	Aquamarine:           {127, 255, 212, 255},
+	Azure:                {240, 255, 255, 255},
+	Beige:                {245, 245, 220, 255},
+	Bisque:               {255, 228, 196, 255},
+	Black:                {0, 0, 0, 255},
+	Blanchedalmond:       {255, 235, 205, 255},
+	Blue:                 {0, 0, 255, 255},
+	Blueviolet:           {138, 43, 226, 255},
+	Brown:                {165, 42, 42, 255},
+	Burlywood:            {222, 184, 135, 255},
+	Cadetblue:            {95, 158, 160, 255},
+	Chartreuse:           {127, 255, 0, 255},
+	Chocolate:            {210, 105, 30, 255},
+	Coral:                {255, 127, 80, 255},
+	Cornflowerblue:       {100, 149, 237, 255},
+	Cornsilk:             {255, 248, 220, 255},
+	Crimson:              {220, 20, 60, 255},
+	Cyan:                 {0, 255, 255, 255},
+	Darkblue:             {0, 0, 139, 255},
+	Darkcyan:             {0, 139, 139, 255},
+	Darkgoldenrod:        {184, 134, 11, 255},
+	Darkgray:             {169, 169, 169, 255},
+	Darkgreen:            {0, 100, 0, 255},
+	Darkgrey:             {169, 169, 169, 255},
+	Darkkhaki:            {189, 183, 107, 255},
+	Darkmagenta:          {139, 0, 139, 255},
+	Darkolivegreen:       {85, 107, 47, 255},
+	Darkorange:           {255, 140, 0, 255},
+	Darkorchid:           {153, 50, 204, 255},
+	Darkred:              {139, 0, 0, 255},
+	Darksalmon:           {233, 150, 122, 255},
+	Darkseagreen:         {143, 188, 143, 255},
+	Darkslateblue:        {72, 61, 139, 255},
+	Darkslategray:        {47, 79, 79, 255},
+	Darkslategrey:        {47, 79, 79, 255},
+	Darkturquoise:        {0, 206, 209, 255},
+	Darkviolet:           {148, 0, 211, 255},
+	Deeppink:             {255, 20, 147, 255},
+	Deepskyblue:          {0, 191, 255, 255},
+	Dimgray:              {105, 105, 105, 255},
+	Dodgerblue:           {30, 144, 255, 255},
+	Firebrick:            {178, 34, 34, 255},
+	Floralwhite:          {255, 250, 240, 255},
+	Forestgreen:          {34, 139, 34, 255},
+	Fuchsia:              {255, 0, 255, 255},
+	Gainsboro:            {220, 220, 220, 255},
+	Ghostwhite:           {248, 248, 255, 255},
+	Gold:                 {255, 215, 0, 255},
+	Goldenrod:            {218, 165, 32, 255},
+	Gray:                 {128, 128, 128, 255},
+	Grey:                 {128, 128, 128, 255},
+	Green:                {0, 128, 0, 255},
+	Greenyellow:          {173, 255, 47, 255},
+	Honeydew:             {240, 255, 240, 255},
+	Hotpink:              {255, 105, 180, 255},
+	Indianred:            {205, 92, 92, 255},
+	Indigo:               {75, 0, 130, 255},
+	Ivory:                {255, 255, 240, 255},
+	Khaki:                {240, 230, 140, 255},
+	Lavender:             {230, 230, 250, 255},
+	Lavenderblush:        {255, 240, 245, 255},
+	Lawngreen:            {124, 252, 0, 255},
+	Lemonchiffon:         {255, 250, 205, 255},
+	Lightblue:            {173, 216, 230, 255},
+	Lightcoral:           {240, 128, 128, 255},
+	Lightcyan:            {224, 255, 255, 255},
+	Lightgoldenrodyellow: {250, 250, 210, 255},
+	Lightgray:            {211, 211, 211, 255},
+	Lightgreen:           {144, 238, 144, 255},
+	Lightgrey:            {211, 211, 211, 255},
+	Lightpink:            {255, 182, 193, 255},
+	Lightsalmon:          {255, 160, 122, 255},
+	Lightseagreen:        {32, 178, 170, 255},
+	Lightskyblue:         {135, 206, 250, 255},
+	Lightslategray:       {119, 136, 153, 255},
+	Lightslategrey:       {119, 136, 153, 255},
+	Lightsteelblue:       {176, 196, 222, 255},
+	Lightyellow:          {255, 255, 224, 255},
+	Lime:                 {0, 255, 0, 255},
+	Limegreen:            {50, 205, 50, 255},
+	Linen:                {250, 240, 230, 255},
+	Magenta:              {255, 0, 255, 255},
+	Maroon:               {128, 0, 0, 255},
+	Mediumaquamarine:     {102, 205, 170, 255},
+	Mediumblue:           {0, 0, 205, 255},
+	Mediumorchid:         {186, 85, 211, 255},
+	Mediumpurple:         {147, 112, 219, 255},
+	Mediumseagreen:       {60, 179, 113, 255},
+	Mediumslateblue:      {123, 104, 238, 255},
+	Mediumspringgreen:    {0, 250, 154, 255},
+	Mediumturquoise:      {72, 209, 204, 255},
+	Mediumvioletred:      {199, 21, 133, 255},
+	Midnightblue:         {25, 25, 112, 255},
+	Mintcream:            {245, 255, 250, 255},
+	Mistyrose:            {255, 228, 225, 255},
+	Moccasin:             {255, 228, 181, 255},
+	Navajowhite:          {255, 222, 173, 255},
+	Navy:                 {0, 0, 128, 255},
+	Oldlace:              {253, 245, 230, 255},
+	Olive:                {128, 128, 0, 255},
+	Olivedrab:            {107, 142, 35, 255},
+	Orange:               {255, 165, 0, 255},
+	Orangered:            {255, 69, 0, 255},
+	Orchid:               {218, 112, 214, 255},
+	Palegoldenrod:        {238, 232, 170, 255},
+	Palegreen:            {152, 251, 152, 255},
+	Paleturquoise:        {175, 238, 238, 255},
+	Palevioletred:        {219, 112, 147, 255},
+	Papayawhip:           {255, 239, 213, 255},
+	Peachpuff:            {255, 218, 185, 255},
+	Peru:                 {205, 133, 63, 255},
+	Pink:                 {255, 192, 203, 255},
+	Plum:                 {221, 160, 221, 255},
+	Powderblue:           {176, 224, 230, 255},
+	Purple:               {128, 0, 128, 255},
+	Red:                  {255, 0, 0, 255},
+	Rosybrown:            {188, 143, 143, 255},
+	Royalblue:            {65, 105, 225, 255},
+	Saddlebrown:          {139, 69, 19, 255},
+	Salmon:               {250, 128, 114, 255},
+	Sandybrown:           {244, 164, 96, 255},
+	Seagreen:             {46, 139, 87, 255},
+	Seashell:             {255, 245, 238, 255},
+	Sienna:               {160, 82, 45, 255},
+	Silver:               {192, 192, 192, 255},
+	Skyblue:              {135, 206, 235, 255},
+	Slateblue:            {106, 90, 205, 255},
+	Slategray:            {112, 128, 144, 255},
+	Slategrey:            {112, 128, 144, 255},
+	Snow:                 {255, 250, 250, 255},
+	Springgreen:          {0, 255, 127, 255},
+	Steelblue:            {70, 130, 180, 255},
+	Tan:                  {210, 180, 140, 255},
+	Teal:                 {0, 128, 128, 255},
+	Thistle:              {216, 191, 216, 255},
+	Tomato:               {255, 99, 71, 255},
+	Turquoise:            {64, 224, 208, 255},
+	Violet:               {238, 130, 238, 255},
+	Wheat:                {245, 222, 179, 255},
+	White:                {255, 255, 255, 255},
+	Whitesmoke:           {245, 245, 245, 255},
+	Yellow:               {255, 255, 0, 255},
+	Yellowgreen:          {154, 205, 50, 255},
+}
+
+var (
+	c      *ring.Ring
+	first  *ring.Ring // Aliceblue, where the ring starts in deterministic mode
+	random *rand.Rand // Generator of deterministic mode, nil to use the random one of math/rand
+	ringMu sync.Mutex // Guards c and random
+)
 
 func init() {
 	count := 146
@@ -164,6 +321,7 @@ func init() {
 
 	// Set random start point
 	defer func() {
+		first = c
 		for i := 0; i < rand.Intn(count); i++ {
 			c = c.Next()
 		}
@@ -462,16 +620,34 @@ func init() {
 	c = c.Next()
 }
 
+// Next named colour from a package wide ring with random start.
+// Prefer NextColour of a document, which gives the same colours on every run.
 func GetColour() (ret string) {
+	ringMu.Lock()
+	defer ringMu.Unlock()
 	ret = c.Value.(string)
 	c = c.Next()
 	return
 }
 
 func GetRandomColour() string {
+	ringMu.Lock()
+	defer ringMu.Unlock()
+	if random != nil {
+		return RGB(random.Intn(256), random.Intn(256), random.Intn(256))
+	}
 	return RGB(rand.Intn(256), rand.Intn(256), rand.Intn(256))
 }
 
+// Make GetColour and GetRandomColour give the same colours on every run, from seed.
+// Documents are always deterministic, as their colours are seeded by SetColourSeed.
+func Deterministic(seed int64) {
+	ringMu.Lock()
+	defer ringMu.Unlock()
+	random = rand.New(rand.NewSource(seed))
+	c = first.Move(random.Intn(first.Len()))
+}
+
 func RGB(r, g, b int) string {
 	var ret string = "rgb("
 	for _, v := range []int{r, g, b} {
@@ -0,0 +1,169 @@
+package smartSVG
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Options of Write and Encoder
+type WriteOption int
+
+const (
+	CDATA WriteOption = iota // Write contents of style and script as CDATA sections instead of escaping them
+)
+
+// Encoder writes elements to w as they are created, without building a tree.
+// The output is identical to Write of the same tree.
+// Writes are not buffered, so wrap w in a bufio.Writer when writing many elements.
+type Encoder struct {
+	w       io.Writer
+	open    []string // Tags of groups not yet ended, outermost first
+	pending bool     // Start tag of the innermost group is not yet terminated
+	cdata   bool
+	format  NumberFormat
+	err     error
+}
+
+func NewEncoder(w io.Writer, options ...WriteOption) *Encoder {
+	e := &Encoder{w: w, format: defaultNumberFormat}
+	for _, o := range options {
+		switch o {
+		case CDATA:
+			e.cdata = true
+		}
+	}
+	return e
+}
+
+// Write b, keeping the first error
+func (e *Encoder) write(b []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(b)
+}
+
+// Terminate start tag of the innermost group, as it gets content
+func (e *Encoder) content() {
+	if e.pending {
+		e.write([]byte(">\n"))
+		e.pending = false
+	}
+}
+
+// Start tag of element at the current level. The outermost element gets the default namespaces as with Write.
+func (e *Encoder) start(tag string, a Att) {
+	e.content()
+	if len(e.open) == 0 {
+		atts := make(Att, len(a)+len(defaultNamespace))
+		for k, v := range a {
+			atts[k] = v
+		}
+		for k, v := range defaultNamespace {
+			atts[k] = v
+		}
+		a = atts
+	}
+	e.write(startTag(tag, a, len(e.open), e.format))
+}
+
+// Set format of numbers in attributes written from now on. Trees given to Encode use their own format if they have one.
+func (e *Encoder) SetNumberFormat(f NumberFormat) {
+	e.format = f
+}
+
+// Start group which is ended by End. Children are written by the following calls.
+func (e *Encoder) Start(tag string, a Att) error {
+	e.start(tag, a)
+	e.open = append(e.open, tag)
+	e.pending = true
+	return e.err
+}
+
+// End innermost group
+func (e *Encoder) End() error {
+	if len(e.open) == 0 {
+		return errors.New("No group to end")
+	}
+	tag := e.open[len(e.open)-1]
+	e.open = e.open[:len(e.open)-1]
+	if e.pending {
+		e.write([]byte(" />\n"))
+		e.pending = false
+	} else {
+		e.write(append(bytes.Repeat([]byte("\t"), len(e.open)), "</"+tag+">\n"...))
+	}
+	return e.err
+}
+
+// Write element without children
+func (e *Encoder) Element(tag string, a Att) error {
+	e.start(tag, a)
+	e.write([]byte(" />\n"))
+	return e.err
+}
+
+// Write element holding data, such as text. Empty data gives an element without content as with Write.
+func (e *Encoder) Data(tag string, a Att, data string) error {
+	if data == "" {
+		return e.Element(tag, a)
+	}
+	if e.cdata && (tag == "style" || tag == "script") {
+		// Split the end marker of CDATA sections between two sections
+		data = "<![CDATA[" + strings.Replace(data, "]]>", "]]]]><![CDATA[>", -1) + "]]>"
+	} else {
+		data = textEscaper.Replace(data)
+	}
+	e.start(tag, a)
+	e.write([]byte(">" + data + "</" + tag + ">\n"))
+	return e.err
+}
+
+// Write comment, which belongs to the element written next.
+// Comments containing "--" or ending with "-" cannot be written and give an error.
+func (e *Encoder) Comment(comment string) error {
+	if strings.Contains(comment, "--") || strings.HasSuffix(comment, "-") {
+		if e.err == nil {
+			e.err = errors.New("Comment " + comment + " contains -- or ends with -")
+		}
+		return e.err
+	}
+	e.content()
+	e.write([]byte("<!--" + comment + "-->\n"))
+	return e.err
+}
+
+// Write tree s at the current level, so that prepared groups can be mixed with streamed ones
+func (e *Encoder) Encode(s *SVG) error {
+	if s.numbers != nil {
+		defer e.SetNumberFormat(e.format)
+		e.format = *s.numbers
+	}
+	if len(e.open) == 0 && s.declaration != "" {
+		e.write([]byte(s.declaration))
+	}
+	for _, c := range s.comments {
+		e.Comment(c)
+	}
+	switch {
+	case len(s.mids) != 0:
+		e.Start(s.tag, s.a)
+		for _, c := range s.mids {
+			e.Encode(c)
+		}
+		e.End()
+	default:
+		e.Data(s.tag, s.a, s.data)
+	}
+	return e.err
+}
+
+// End all open groups and return the first error
+func (e *Encoder) Close() error {
+	for len(e.open) > 0 {
+		e.End()
+	}
+	return e.err
+}
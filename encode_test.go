@@ -0,0 +1,138 @@
+package smartSVG
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewDocument(t *testing.T) {
+	s := New(10, 10)
+	want := `<?xml version="1.0"?>
+<!-- Generated by smartSVG -->
+<svg preserveAspectRatio="xMinYmin meet" viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" />
+`
+	if got := s.String(); got != want {
+		t.Errorf("New document is written as\n%s\nwant\n%s", got, want)
+	}
+	if issues := s.Validate(); len(issues) != 0 {
+		t.Errorf("New document has issues %v", issues)
+	}
+	s.SetCSS("style.css")
+	if got := s.String(); !strings.HasPrefix(got, `<?xml version="1.0"?>`+"\n"+`<?xml-stylesheet type="text/css" href="style.css" ?>`+"\n") {
+		t.Errorf("Document with style sheet is written as\n%s", got)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	s := New(10, 10)
+	s.Text(0, 0, `a < b & "c" > d`, Att{"font-family": `"Times" & <serif>`})
+	s.Style("p > a { content: \"]]>\" }")
+	var plain, cdata bytes.Buffer
+	if err := s.Write(&plain); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(&cdata, CDATA); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`font-family="&quot;Times&quot; &amp; &lt;serif&gt;"`,
+		`>a &lt; b &amp; "c" &gt; d</text>`,
+		`>p &gt; a { content: "]]&gt;" }</style>`,
+	} {
+		if !strings.Contains(plain.String(), want) {
+			t.Errorf("Output has no %s:\n%s", want, plain.String())
+		}
+	}
+	if want := `><![CDATA[p > a { content: "]]]]><![CDATA[>" }]]></style>`; !strings.Contains(cdata.String(), want) {
+		t.Errorf("Output with CDATA has no %s:\n%s", want, cdata.String())
+	}
+
+	// Escaped output reads back as the same tree
+	p, err := Parse(&plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.String(); got != s.String() {
+		t.Errorf("Parsed document is written as\n%s\nwant\n%s", got, s.String())
+	}
+}
+
+func TestComment(t *testing.T) {
+	for _, comment := range []string{"a -- b", "ends with -"} {
+		s := New(10, 10)
+		s.Comment(comment)
+		if err := s.Write(&bytes.Buffer{}); err == nil {
+			t.Errorf("Write of comment %q gave no error", comment)
+		}
+	}
+	s := New(10, 10)
+	s.G(nil).Comment(" a - b ")
+	if got := s.String(); !strings.Contains(got, "<!-- a - b -->\n") {
+		t.Errorf("Comment is missing from\n%s", got)
+	}
+}
+
+// Writer failing after n bytes
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(b []byte) (int, error) {
+	if len(b) > w.n {
+		return w.n, errors.New("full")
+	}
+	w.n -= len(b)
+	return len(b), nil
+}
+
+func TestWriteError(t *testing.T) {
+	s := New(10, 10)
+	for i := 0; i < 10; i++ {
+		s.Rect(i, i, 1, 1, nil)
+	}
+	if err := s.Write(&failingWriter{100}); err == nil || err.Error() != "full" {
+		t.Errorf("Write to failing writer gave error %v, want full", err)
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	s := New(100, 100)
+	g := s.G(Translate(1.5, 2))
+	g.Rect(0, 0, 10, 10, Att{"fill": "red"})
+	g.Text(5, 5, "a < b", nil)
+	s.Style("p { }")
+	s.Circle(50, 50, 10, nil)
+	want := strings.TrimPrefix(s.String(), xmlDeclaration+"<!--"+generatedComment+"-->\n")
+
+	// Stream the same document, mixing streamed groups with prepared ones
+	var b bytes.Buffer
+	e := NewEncoder(&b)
+	e.Start("svg", s.a)
+	e.Start("g", g.a)
+	e.Element("rect", g.mids[0].a)
+	e.Encode(g.mids[1])
+	e.End()
+	e.Data("style", Att{"type": "text/css"}, "p { }")
+	if err := e.Encode(s.mids[2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != want {
+		t.Errorf("Encoder wrote\n%s\nwant\n%s", b.String(), want)
+	}
+	if err := e.End(); err == nil {
+		t.Error("End without open group gave no error")
+	}
+
+	b.Reset()
+	e = NewEncoder(&b)
+	e.SetNumberFormat(Decimals(1))
+	e.Element("circle", Att{"r": 1.26})
+	if got := b.String(); !strings.HasPrefix(got, `<circle r="1.3" `) {
+		t.Errorf("Encoder with one decimal wrote %s", got)
+	}
+}
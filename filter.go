@@ -0,0 +1,149 @@
+package smartSVG
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Standard inputs of filter primitives
+const (
+	SourceGraphic   = "SourceGraphic"
+	SourceAlpha     = "SourceAlpha"
+	BackgroundImage = "BackgroundImage"
+	BackgroundAlpha = "BackgroundAlpha"
+	FillPaint       = "FillPaint"
+	StrokePaint     = "StrokePaint"
+)
+
+// Create filter. Add filter primitives to the returned group. Should be placed inside defs.
+// Ref http://www.w3.org/TR/SVG11/filters.html#FilterElement
+func (s *SVG) Filter(id string, a Att) *SVG {
+	g := s.newGroup("filter", a)
+	g.a["id"] = id
+	return g
+}
+
+// Apply filter with given id to group
+func (s *SVG) SetFilter(id string) {
+	s.a["filter"] = URL(id)
+}
+
+// Create filter primitive. Empty in, in2 and result are left out.
+func (s *SVG) primitive(tag, in, in2, result string) *SVG {
+	g := s.newGroup(tag, nil)
+	for k, v := range map[string]string{"in": in, "in2": in2, "result": result} {
+		if v != "" {
+			g.a[k] = v
+		}
+	}
+	return g
+}
+
+// Blur input with standard deviation stdDev
+func (s *SVG) FeGaussianBlur(in string, stdDev float64, result string) *SVG {
+	g := s.primitive("feGaussianBlur", in, "", result)
+	g.a["stdDeviation"] = stdDev
+	return g
+}
+
+// Move input by dx, dy
+func (s *SVG) FeOffset(in string, dx, dy float64, result string) *SVG {
+	g := s.primitive("feOffset", in, "", result)
+	g.a["dx"] = dx
+	g.a["dy"] = dy
+	return g
+}
+
+// Fill filter region with colour
+func (s *SVG) FeFlood(colour string, opacity float64, result string) *SVG {
+	g := s.primitive("feFlood", "", "", result)
+	g.a["flood-color"] = colour
+	g.a["flood-opacity"] = opacity
+	return g
+}
+
+// Combine in and in2 with operator over, in, out, atop, xor or arithmetic
+func (s *SVG) FeComposite(in, in2, operator, result string) *SVG {
+	g := s.primitive("feComposite", in, in2, result)
+	g.a["operator"] = operator
+	return g
+}
+
+// Layer inputs on top of each other, the first input at the bottom
+func (s *SVG) FeMerge(result string, inputs ...string) *SVG {
+	g := s.primitive("feMerge", "", "", result)
+	for _, in := range inputs {
+		g.newGroup("feMergeNode", Att{"in": in})
+	}
+	return g
+}
+
+// Transform colours of input. The number of values depends on typ:
+// 20 for matrix, 1 for saturate and hueRotate and none for luminanceToAlpha.
+func (s *SVG) FeColorMatrix(in, typ string, values []float64, result string) (*SVG, error) {
+	count := map[string]int{"matrix": 20, "saturate": 1, "hueRotate": 1, "luminanceToAlpha": 0}
+	n, ok := count[typ]
+	switch {
+	case !ok:
+		return nil, errors.New("Got unknown colour matrix type " + typ)
+	case len(values) != n:
+		return nil, errors.New("Colour matrix of type " + typ + " takes " + fmt.Sprint(n) + " values, got " + fmt.Sprint(len(values)))
+	}
+
+	g := s.primitive("feColorMatrix", in, "", result)
+	g.a["type"] = typ
+	if n > 0 {
+		g.a["values"] = numberList{values: values}
+	}
+	return g, nil
+}
+
+// Blend in and in2 with mode normal, multiply, screen, darken or lighten
+func (s *SVG) FeBlend(in, in2, mode, result string) *SVG {
+	g := s.primitive("feBlend", in, in2, result)
+	g.a["mode"] = mode
+	return g
+}
+
+// Thin or fatten input with operator erode or dilate
+func (s *SVG) FeMorphology(in, operator string, radius float64, result string) *SVG {
+	g := s.primitive("feMorphology", in, "", result)
+	g.a["operator"] = operator
+	g.a["radius"] = radius
+	return g
+}
+
+// Create Perlin noise of type turbulence or fractalNoise
+func (s *SVG) FeTurbulence(typ string, baseFrequency float64, numOctaves int, seed float64, result string) *SVG {
+	g := s.primitive("feTurbulence", "", "", result)
+	g.a["type"] = typ
+	g.a["baseFrequency"] = baseFrequency
+	g.a["numOctaves"] = numOctaves
+	g.a["seed"] = seed
+	return g
+}
+
+// Region large enough to hold blurred and offset output
+var filterRegion = Att{"x": "-50%", "y": "-50%", "width": "200%", "height": "200%"}
+
+// Create filter drawing a blurred shadow of colour below the graphic, moved by dx, dy.
+func (s *SVG) DropShadow(id string, dx, dy, blur float64, colour string) *SVG {
+	g := s.Filter(id, filterRegion)
+	g.FeGaussianBlur(SourceAlpha, blur, "blur")
+	g.FeOffset("blur", dx, dy, "offset")
+	g.FeFlood(colour, 1, "colour")
+	g.FeComposite("colour", "offset", "in", "shadow")
+	g.FeMerge("", "shadow", SourceGraphic)
+	return g
+}
+
+// Create filter drawing a blurred halo of colour around the graphic
+func (s *SVG) Glow(id string, blur float64, colour string) *SVG {
+	g := s.Filter(id, filterRegion)
+	g.FeMorphology(SourceAlpha, "dilate", blur/2, "spread")
+	g.FeGaussianBlur("spread", blur, "blur")
+	g.FeFlood(colour, 1, "colour")
+	g.FeComposite("colour", "blur", "in", "glow")
+	g.FeMerge("", "glow", SourceGraphic)
+	return g
+}
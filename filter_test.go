@@ -0,0 +1,62 @@
+package smartSVG
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFeColorMatrix(t *testing.T) {
+	tests := []struct {
+		typ    string
+		values []float64
+		want   string // Values attribute, or empty for none
+		ok     bool
+	}{
+		{"saturate", []float64{0.5}, "0.5", true},
+		{"hueRotate", []float64{90}, "90", true},
+		{"luminanceToAlpha", nil, "", true},
+		{"matrix", make([]float64, 20), strings.Repeat("0 ", 19) + "0", true},
+		{"matrix", make([]float64, 4), "", false},
+		{"saturate", nil, "", false},
+		{"sepia", nil, "", false},
+	}
+	for _, test := range tests {
+		f := New(10, 10).Def().Filter("f", nil)
+		g, err := f.FeColorMatrix(SourceGraphic, test.typ, test.values, "out")
+		switch {
+		case !test.ok:
+			if err == nil {
+				t.Errorf("Colour matrix %s of %v gave no error", test.typ, test.values)
+			}
+		case err != nil:
+			t.Errorf("Colour matrix %s of %v gave error %v", test.typ, test.values, err)
+		case test.want == "" && g.a["values"] != nil:
+			t.Errorf("Colour matrix %s has values %v, want none", test.typ, g.a["values"])
+		case test.want != "" && g.a["values"].(numberList).String() != test.want:
+			t.Errorf("Colour matrix %s has values %v, want %s", test.typ, g.a["values"], test.want)
+		}
+	}
+}
+
+func TestDropShadow(t *testing.T) {
+	s := New(100, 100)
+	s.Def().DropShadow("shadow", 2, 3, 1.5, "black")
+	s.Rect(10, 10, 50, 50, nil).SetFilter("shadow")
+	got := s.String()
+	for _, want := range []string{
+		`<filter height="200%" id="shadow" width="200%" x="-50%" y="-50%">`,
+		`<feGaussianBlur in="SourceAlpha" result="blur" stdDeviation="1.5" />`,
+		`<feOffset dx="2" dy="3" in="blur" result="offset" />`,
+		`<feComposite in="colour" in2="offset" operator="in" result="shadow" />`,
+		`<feMergeNode in="shadow" />`,
+		`<feMergeNode in="SourceGraphic" />`,
+		`filter="url(#shadow)"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Document with drop shadow has no %s:\n%s", want, got)
+		}
+	}
+	if issues := s.Validate(); len(issues) != 0 {
+		t.Errorf("Document with drop shadow has issues %v", issues)
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"io"
 	"math"
 	"sort"
-	"strconv"
 	"strings"
 )
 
@@ -23,7 +22,7 @@ func fold(init float64, f func(float64, float64) float64, vals ...float64) float
 
 func reduce(f func(float64, float64) float64, vals ...float64) float64 {
 	a := vals[0]
-	for _, b := range vals {
+	for _, b := range vals[1:] {
 		a = f(a, b)
 	}
 
@@ -45,7 +44,7 @@ func min(vals ...float64) float64 {
 			return a
 		}
 		return b
-	})
+	}, vals...)
 }
 
 func average(vals ...float64) float64 {
@@ -54,13 +53,6 @@ func average(vals ...float64) float64 {
 	}, vals...) / float64(len(vals))
 }
 
-func round(a float64) int {
-	if a > 0.0 {
-		return int(a + 0.5)
-	}
-	return int(a - 0.5)
-}
-
 // Sort atts easily
 type keySorter struct {
 	keys []string
@@ -99,10 +91,12 @@ type Data struct {
 
 type Att map[string]interface{}
 
+// Attributes as written by Write, in sorted order
 func (a Att) String() string {
 	str := ""
-	for k, v := range a {
-		str += k + "=\"" + fmt.Sprint(v) + `" `
+	keys, vals := a.Sort()
+	for i, k := range keys {
+		str += k + "=\"" + attEscaper.Replace(defaultNumberFormat.value(vals[i])) + `" `
 	}
 	return str[:len(str)]
 }
@@ -127,6 +121,10 @@ type SVG struct {
 	mids        []*SVG
 	parent      *SVG
 	declaration string
+	palette     *Palette         // Palette of diagrams within group, nil to use the one of the parent
+	colours     *colourAllocator // Colour allocation of group and its children, nil to use the one of the parent
+	numbers     *NumberFormat    // Format of numbers within group, nil to use the one of the parent
+	added       []*SVG           // Series of diagram in the order they were added, nil for diagrams read by Parse
 }
 
 func (s *SVG) String() string {
@@ -138,7 +136,8 @@ func (s *SVG) String() string {
 }
 
 const (
-	svgInit = `<?xml version="1.0"?>` + "\n" + `<!-- Generated by smartSVG -->`
+	xmlDeclaration   = `<?xml version="1.0"?>` + "\n"
+	generatedComment = " Generated by smartSVG "
 )
 
 var defaultNamespace map[string]string
@@ -161,7 +160,7 @@ func (s *SVG) IsParent(p *SVG) bool {
 
 // Create new SVG object to write to
 func New(width, height int) *SVG {
-	return &SVG{tag: "svg", data: svgInit, mids: make([]*SVG, 0), comments: make([]string, 0), a: Att{"preserveAspectRatio": "xMinYmin meet", "viewBox": "0 0 " + fmt.Sprint(width, " ", height)}}
+	return &SVG{tag: "svg", declaration: xmlDeclaration, mids: make([]*SVG, 0), comments: []string{generatedComment}, a: Att{"preserveAspectRatio": "xMinYmin meet", "viewBox": "0 0 " + fmt.Sprint(width, " ", height)}}
 }
 
 // Creates child group nested from s
@@ -206,68 +205,53 @@ func (s *SVG) Insert(svg *SVG) (*SVG, error) {
 	return svg, nil
 }
 
-// Write svg file to w
-func (s *SVG) Write(w io.Writer) error {
-	//	if s.tag != "" {
-	//		return errors.New("I will only write from the outermost svg element")
-	//	}
-	for k, v := range defaultNamespace {
-		s.a[k] = v
-	}
-
-	var writeGroupPtr func(*SVG, int)
-	writeGroup := func(s *SVG, level int) {
-		tabs := bytes.Repeat([]byte("\t"), level)
+// Indented start tag of element with sorted attributes, without its terminator. Numbers are written with format f.
+func startTag(tag string, a Att, level int, f NumberFormat) []byte {
+	buf := bytes.NewBuffer(bytes.Repeat([]byte("\t"), level))
+	buf.WriteString("<" + tag)
 
-		// Encode attributes to form att1="val1" att2="val2"...
-		buf := bytes.NewBuffer(nil)
-		keys, vals := s.a.Sort()
-		for i := range keys {
-			fmt.Fprintf(buf, `%s="%v" `, keys[i], vals[i])
-		}
-
-		atts := buf.Bytes()
-		if len(atts) > 0 { // Remove superfluous space
-			atts = atts[:len(atts)-1]
-		}
+	// Encode attributes to form att1="val1" att2="val2"...
+	keys, vals := a.Sort()
+	for i := range keys {
+		buf.WriteString(" " + keys[i] + `="`)
+		attEscaper.WriteString(buf, f.value(vals[i]))
+		buf.WriteString(`"`)
+	}
+	return buf.Bytes()
+}
 
-		for _, v := range s.comments {
-			w.Write([]byte("<!--" + v + "-->\n"))
-		}
+// Escape attribute values and character data. Whitespace in attributes is kept by character references.
+var (
+	attEscaper  = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+)
 
-		w.Write(tabs)
-		w.Write([]byte("<" + s.tag))
-		if len(atts) != 0 {
-			w.Write([]byte(" "))
-		}
-		w.Write(atts)
-
-		switch {
-		case len(s.mids) == 0 && len(s.data) == 0:
-			w.Write([]byte(" />\n"))
-		case len(s.mids) != 0:
-			w.Write([]byte(">\n"))
-			for _, v := range s.mids {
-				writeGroupPtr(v, level+1)
-			}
-			w.Write(tabs)
-			w.Write([]byte("</" + s.tag + ">\n"))
-		default:
-			w.Write([]byte(">" + s.data + "</" + s.tag + ">\n"))
-		}
-	}
-	if s.declaration != "" {
-		w.Write([]byte(s.declaration))
-	}
-	writeGroupPtr = writeGroup
-	writeGroup(s, 0)
-	return nil
+// Write svg file to w. Returns the first error from w.
+func (s *SVG) Write(w io.Writer, options ...WriteOption) error {
+	e := NewEncoder(w, options...)
+	e.SetNumberFormat(s.numberFormat())
+	e.Encode(s)
+	return e.Close()
 }
 
-// Add attributes to group
+// Add attributes to group. Transforms are composed unless override is set.
 func (s *SVG) AddAtt(override bool, a ...Att) {
 	for _, att := range a {
 		for k, v := range att {
+			if isTransform(k) {
+				if t, err := toTransform(v); err == nil {
+					if w, ok := s.a[k]; ok && !override {
+						if u, err := toTransform(w); err == nil {
+							s.a[k] = u.Multiply(t)
+							continue
+						}
+					} else {
+						s.a[k] = t
+						continue
+					}
+				}
+			}
+
 			var tmp string
 			if !override {
 				if w, ok := s.a[k]; ok {
@@ -297,9 +281,13 @@ func (s *SVG) StartView(width, height, minX, minY, viewWidth, viewHeight int, a
 	return g
 }
 
-// Set information about external style sheet
+// Set information about external style sheet, after the xml declaration if there is one
 func (s *SVG) SetCSS(href string) {
-	s.declaration = `<?xml-stylesheet type="text/css" href="` + href + "\" ?>\n"
+	var xml string
+	if strings.HasPrefix(s.declaration, "<?xml ") {
+		xml = s.declaration[:strings.Index(s.declaration, "?>")+2] + "\n"
+	}
+	s.declaration = xml + `<?xml-stylesheet type="text/css" href="` + attEscaper.Replace(href) + "\" ?>\n"
 }
 
 // Create symbol group
@@ -342,6 +330,13 @@ func (s *SVG) Desc(description string) *SVG {
 	return g
 }
 
+// Create embedded style sheet. Write it with the CDATA option to keep it readable.
+func (s *SVG) Style(css string) *SVG {
+	g := s.newGroup("style", Att{"type": "text/css"})
+	g.data = css
+	return g
+}
+
 // Create definitions
 func (s *SVG) Def() *SVG {
 	return s.newGroup("defs", nil)
@@ -373,24 +368,27 @@ func (s *SVG) ID(id string) {
 
 // Create group with translation of coordinate system
 func (s *SVG) Translate(x, y float64) *SVG {
-	a := Att{"transform": fmt.Sprintf("translate(%g, %g)", x, y)}
-	return s.newGroup("g", a)
+	return s.newGroup("g", Translate(x, y))
 }
 
 // Create group with scale of coordinate system
 func (s *SVG) Scale(x, y float64) *SVG {
-	a := Att{"transform": fmt.Sprintf("scale(%g, %g)", x, y)}
-	return s.newGroup("g", a)
+	return s.newGroup("g", Scale(x, y))
 }
 
 // Make translation attribute of coordinate system
 func Translate(x, y float64) Att {
-	return Att{"transform": fmt.Sprintf("translate(%g, %g)", x, y)}
+	return Att{"transform": Identity().Translate(x, y)}
 }
 
 // Make scale attribute of coordinate system
 func Scale(x, y float64) Att {
-	return Att{"transform": fmt.Sprintf("scale(%g, %g)", x, y)}
+	return Att{"transform": Identity().Scale(x, y)}
+}
+
+// Make rotation attribute of coordinate system, angle given in degrees
+func Rotate(angle float64) Att {
+	return Att{"transform": Identity().Rotate(angle)}
 }
 
 func ViewBox(x, y, xMin, yMin int) Att {
@@ -422,6 +420,25 @@ func (s *SVG) Circle(x, y, r int, a Att) *SVG {
 	return g
 }
 
+// Draw circle with float coordinates
+func (s *SVG) CircleF(x, y, r float64, a Att) *SVG {
+	g := s.newGroup("circle", a)
+	g.a["cx"] = x
+	g.a["cy"] = y
+	g.a["r"] = r
+	return g
+}
+
+// Draw ellipse
+func (s *SVG) Ellipse(x, y, rx, ry float64, a Att) *SVG {
+	g := s.newGroup("ellipse", a)
+	g.a["cx"] = x
+	g.a["cy"] = y
+	g.a["rx"] = rx
+	g.a["ry"] = ry
+	return g
+}
+
 // Draw rectangle
 func (s *SVG) Rect(x, y, width, height int, a Att) *SVG {
 	g := s.newGroup("rect", a)
@@ -432,6 +449,24 @@ func (s *SVG) Rect(x, y, width, height int, a Att) *SVG {
 	return g
 }
 
+// Draw rectangle with float coordinates
+func (s *SVG) RectF(x, y, width, height float64, a Att) *SVG {
+	g := s.newGroup("rect", a)
+	g.a["x"] = x
+	g.a["y"] = y
+	g.a["width"] = width
+	g.a["height"] = height
+	return g
+}
+
+// Draw rectangle with corners rounded by the radii rx and ry
+func (s *SVG) RoundedRect(x, y, width, height, rx, ry float64, a Att) *SVG {
+	g := s.RectF(x, y, width, height, a)
+	g.a["rx"] = rx
+	g.a["ry"] = ry
+	return g
+}
+
 // Draw line
 func (s *SVG) Line(x1, y1, x2, y2 int, a Att) *SVG {
 	g := s.newGroup("line", a)
@@ -442,28 +477,54 @@ func (s *SVG) Line(x1, y1, x2, y2 int, a Att) *SVG {
 	return g
 }
 
-// Draw polyline
-func (s *SVG) Polyline(d Data, a Att) (*SVG, error) {
+// Draw line with float coordinates
+func (s *SVG) LineF(x1, y1, x2, y2 float64, a Att) *SVG {
+	g := s.newGroup("line", a)
+	g.a["x1"] = x1
+	g.a["x2"] = x2
+	g.a["y1"] = y1
+	g.a["y2"] = y2
+	return g
+}
+
+// Data in the form used in the points attribute, written with the number format of the document
+func points(d Data) (numberList, error) {
 	switch {
 	case len(d.X) != len(d.Y):
-		return nil, errors.New("length of data pair is not equal")
+		return numberList{}, errors.New("length of data pair is not equal")
 	case len(d.X) == 0:
-		return nil, errors.New("length of data is zero")
+		return numberList{}, errors.New("length of data is zero")
 	}
 
-	encloseData := func(x, y float64) string {
-		return fmt.Sprintf("%f,%f ", x, y)
+	data := numberList{values: make([]float64, 0, 2*len(d.X)), pairs: true}
+	for i := range d.X {
+		data.values = append(data.values, d.X[i], d.Y[i])
 	}
+	return data, nil
+}
 
-	// Create formatted data plots
-	data := make([]byte, 0, len(d.X))
-	for i := range d.X {
-		data = append(data, []byte(encloseData(d.X[i], d.Y[i]))...)
+// Draw polyline
+func (s *SVG) Polyline(d Data, a Att) (*SVG, error) {
+	data, err := points(d)
+	if err != nil {
+		return nil, err
 	}
 
 	// Draw the polyline
 	g := s.newGroup("polyline", a)
-	g.a["points"] = string(data)
+	g.a["points"] = data
+	return g, nil
+}
+
+// Draw closed polygon
+func (s *SVG) Polygon(d Data, a Att) (*SVG, error) {
+	data, err := points(d)
+	if err != nil {
+		return nil, err
+	}
+
+	g := s.newGroup("polygon", a)
+	g.a["points"] = data
 	return g, nil
 }
 
@@ -477,6 +538,16 @@ func (s *SVG) Text(x, y int, text string, a Att) *SVG {
 	return g
 }
 
+// Draw text with float coordinates
+func (s *SVG) TextF(x, y float64, text string, a Att) *SVG {
+	g := s.newGroup("text", a)
+	g.data = text
+	g.a["x"] = x
+	g.a["y"] = y
+
+	return g
+}
+
 func (s *SVG) Image(x, y, width, height int, link string, a Att) *SVG {
 	g := s.newGroup("image", a)
 	g.a["xlink:href"] = link
@@ -486,10 +557,22 @@ func (s *SVG) Image(x, y, width, height int, link string, a Att) *SVG {
 	return g
 }
 
+// Draw image with float coordinates
+func (s *SVG) ImageF(x, y, width, height float64, link string, a Att) *SVG {
+	g := s.newGroup("image", a)
+	g.a["xlink:href"] = link
+	g.a["x"] = x
+	g.a["y"] = y
+	g.a["width"] = width
+	g.a["height"] = height
+
+	return g
+}
+
 // Write text on line from p1 to p2, with cntGrids values as given in vals.
 // Prerequisites: vals[] is linear
 func (s *SVG) Label(x1, y1, x2, y2 int, vals []float64, cntGrids int, a Att) {
-	g := s.GID("label", a)
+	g := s.GID(s.uniqueID("label"), a)
 	g.AddAtt(false, Att{"fill": "black"})
 	xDiff := float64(x2 - x1)
 	yDiff := float64(y2 - y1)
@@ -509,7 +592,7 @@ func (s *SVG) Label(x1, y1, x2, y2 int, vals []float64, cntGrids int, a Att) {
 
 	// Draw text
 	for i := 0; i <= cntGrids; i++ {
-		g.Text(round(x), round(y), fmt.Sprintf("%.2f", val), nil)
+		g.Text(int(math.Round(x)), int(math.Round(y)), fmt.Sprintf("%.2f", val), nil)
 		val += valIncr
 		x += xIncr
 		y += yIncr
@@ -520,11 +603,11 @@ func (s *SVG) Label(x1, y1, x2, y2 int, vals []float64, cntGrids int, a Att) {
 
 // Draw a grid with cntGrids horizontal and vertical lines
 func (s *SVG) Grid(x, y, width, height, cntGrids int, a Att) *SVG {
-	vLine := "vLine"
-	hLine := "hLine"
+	vLine := s.uniqueID("vLine")
+	hLine := s.uniqueID("hLine")
 
 	// Create group with defs
-	g := s.GID("grid", a)
+	g := s.GID(s.uniqueID("grid"), a)
 	d := g.Def()
 	d.Line(0, 0, 0, height, Att{"id": vLine})
 	d.Line(0, 0, width, 0, Att{"id": hLine})
@@ -545,14 +628,15 @@ func (s *SVG) Grid(x, y, width, height, cntGrids int, a Att) *SVG {
 
 // Display modes
 const (
-	Column = iota
-	Continuous
+	Column     = iota // Columns drawn by a marker on the mid vertices of the plot. BarChart draws real bars.
+	Continuous        // Plot drawn as line
+	Scatter           // One marker per point. Use AddScatter for other markers.
 )
 
-// Paint a diagram
+// Paint a diagram, with the axes and layout shared by the charts
 func (s *SVG) Diagram(x, y, width, height int, d Data, title string, display int) (*SVG, error) {
 	switch display {
-	case Column, Continuous:
+	case Column, Continuous, Scatter:
 		break
 	default:
 		return nil, errors.New("Got unknown display mode")
@@ -567,200 +651,239 @@ func (s *SVG) Diagram(x, y, width, height int, d Data, title string, display int
 
 	last := d.X[0]
 	for _, v := range d.X {
-		if v < last {
+		if v < last && display != Scatter {
 			return nil, errors.New("Xvals is not sorted.")
 		}
 		last = v
 	}
 
-	titleHeight := 25
-	textHeight := 10
-	textRoomX := 70
-	textRoomY := textRoomX / 3
-	plotMargin := 2
-	cntGrids := 10
-	dWidth, dHeight := width-textRoomX, height-textRoomY
-
-	top := s.GID("diagram", Att{"width": width, "height": height})
-	top.AddAtt(false, Translate(float64(x), float64(y)))
-
-	// Draw background in order to make whole object clickable, and to set the background of the diagram
-	top.Rect(0, 0, width, height, nil)
-	top.Text(width/2, 3*titleHeight/4, title, Att{"text-anchor": "middle", "fill": "black", "id": "title"})
-
-	// Draw outer frame
-	//defer v.Rect(0, 0, width, height, map[string]string{"stroke": "grey", "stroke-width": "1", "fill": "none"})
-
-	// New group with plot, move to upper right corner
-	g := top.G(Translate(float64(textRoomX), float64(titleHeight)))
-	g.ID("plot")
-
-	// Mark data on the axes
-	alignRight := Att{"text-anchor": "end"}
-	alignLeft := Att{"text-anchor": "start"}
-	// Vertical
-	g.Label(0, height-textRoomY, 0, titleHeight-2*plotMargin, d.Y, cntGrids, alignRight)
-	// Horizontal
-	g.Label(0, height-textRoomY+textHeight, width-textRoomX, height-textRoomY+textHeight, d.X, cntGrids, alignLeft)
-
-	dd := g.StartView(dWidth, dHeight, 0, 0, dWidth, dHeight, nil)
-	cartesian := dd.Translate(0.0, float64(dHeight))
-	cartesian.AddAtt(false, Scale(1, -1), Att{"fill": "none"})
-	// Draw inner frame almost last
-	defer cartesian.Rect(0, 0, dWidth, dHeight, Att{"stroke": "grey", "stroke-width": "3"})
-
-	marginShift := cartesian.Translate(float64(plotMargin), float64(plotMargin))
-	marginShift.AddAtt(false, Scale(float64(dWidth-2*plotMargin)/float64(dWidth), float64(dHeight-2*plotMargin)/float64(dHeight)))
-	marginShift.Grid(0, 0, dWidth, dHeight, cntGrids, Att{"stroke": "black", "stroke-width": "1"})
-	// Finds the scales and shift of data
-	resize := func(vals []float64, length int) (scale, shift float64) {
-		min := min(vals...) // ignoring err because data has already been tested
-		max := max(vals...)
-		scale = float64(length) / (max - min)
-		shift = -min * scale
-		return
-	}
-	xScale, xShift := resize(d.X, dWidth)
-	yScale, yShift := resize(d.Y, dHeight)
-
-	// Scales and shifts the plot
-	plot := marginShift.Translate(xShift, yShift)
-	plot.ID("data")
-	plot.AddAtt(false, Scale(xScale, yScale))
+	for i := range d.X {
+		if math.IsNaN(d.X[i]) || math.IsInf(d.X[i], 0) || math.IsNaN(d.Y[i]) || math.IsInf(d.Y[i], 0) {
+			return nil, errors.New("Point " + fmt.Sprint(d.X[i], ", ", d.Y[i]) + " is not a number")
+		}
+	}
+
+	c, err := s.newChart(x, y, width, height, title)
+	if err != nil {
+		return nil, err
+	}
+	top := c.top
+	xs := niceScale(min(d.X...), max(d.X...), c.width)
+	ys := niceScale(min(d.Y...), max(d.Y...), c.height)
+	c.valueAxis(ys, false)
+	c.valueAxis(xs, true)
+	defer c.frame()
+	colour := top.NextColour().String()
+
+	// Data is drawn in a viewport of the plot area, scaled so that the axes cover it, with the y axis pointing up
+	w, h := int(c.width), int(c.height)
+	view := c.plot.StartView(w, h, 0, 0, w, h, nil)
+	plot := view.GID("data"+top.idSuffix(), Att{"fill": "none"})
+	xScale, yScale := c.width/(xs.hi-xs.lo), c.height/(ys.hi-ys.lo)
+	plot.AddAtt(false, Translate(-xs.lo*xScale, c.height+ys.lo*yScale))
+	plot.AddAtt(false, Scale(xScale, -yScale))
 
 	// Create marker inside defs to be used with plot
 	def := plot.Def()
-	def.Marker("polyline-midmarker", Att{"viewBox": "0 0 10 10",
+
+	// Clip data to plot rectangle, given in data coordinates
+	clip := def.uniqueID("data-clip")
+	def.ClipPath(clip, nil).RectF(xs.lo, ys.lo, xs.hi-xs.lo, ys.hi-ys.lo, nil)
+	plot.SetClipPath(clip)
+
+	def.Marker(def.uniqueID("polyline-midmarker"), Att{"viewBox": "0 0 10 10",
 		"preserveAspectRatio": "xMidYMid meet",
 		"refX":                "5",
 		"refY":                "5",
-		"stroke":              GetColour(),
+		"stroke":              colour,
 		"fill":                "none",
 		"orient":              "auto",
 		"vector-effect":       "non-scaling-stroke"}).Circle(0, 0, 1, nil)
 
 	// Draws the plot
-	att := Att{"fill": "none", "stroke": GetColour(), "vector-effect": "non-scaling-stroke"} //, "marker-mid": "url(#polyline-midmarker)"})
+	att := Att{"fill": "none", "stroke": colour, "vector-effect": "non-scaling-stroke"} //, "marker-mid": "url(#polyline-midmarker)"})
 	switch display {
 	case Continuous:
 		break
 	case Column:
 		// Create marker which stands as columns
 		att["stroke"] = "none"
-		att["marker-mid"] = "url(#column-marker)"
-		def.Marker("column-marker", Att{"viewBox": "0 0 10 10",
-			"preserveAspectRatio": "xMidYMid meet",
-			"refX":                "5",
-			"refY":                "5",
-			"stroke":              GetColour(),
-			"fill":                "none",
-			"orient":              "fixed",
-			"vector-effect":       "non-scaling-stroke"}).Rect(0, 0, 1, 1000, nil)
+		att["marker-mid"] = URL(columnMarker(def, colour, "none"))
+	case Scatter:
+		_, err := top.scatter(plot, d, colour, ScatterOptions{})
+		return top, err
 	}
-	_, err := plot.Polyline(d, att)
-	return top, err
+	line, err := plot.Polyline(d, att)
+	if err != nil {
+		return nil, err
+	}
+	top.addSeries(line)
+	return top, nil
+}
+
+// Create marker drawing a column at every mid vertex of a polyline. Returns id of marker.
+func columnMarker(def *SVG, stroke, fill string) string {
+	id := def.uniqueID("column-marker")
+	def.Marker(id, Att{"viewBox": "0 0 10 10",
+		"preserveAspectRatio": "xMidYMid meet",
+		"refX":                "5",
+		"refY":                "5",
+		"stroke":              stroke,
+		"fill":                fill,
+		"orient":              "fixed",
+		"vector-effect":       "non-scaling-stroke"}).Rect(0, 0, 1, 1000, nil)
+	return id
 }
 
 // Test to prevent adding plot to previous plot not working? // Messes up scale when used?
+// In Column mode, stroke and fill of a are used for the columns. Stroke defaults to the next colour of the palette.
 func (s *SVG) AddPlot(d Data, a Att) (*SVG, error) {
-	if s.a["id"] != "diagram" || len(s.FindGroups("polyline")) == 0 {
+	series := s.series()
+	if !s.isDiagram() || len(series) == 0 {
 		return nil, errors.New("Will only add plot to existing diagram: Could not find id with diagram nor data with polyline groups")
 	}
 
 	var plot *SVG
-	if plot = s.FindID("data"); plot == nil {
+	if plot = s.part("data"); plot == nil {
 		return nil, errors.New("Could not find any existing data to add plot with")
 	}
 
+	if _, ok := a["stroke"]; !ok {
+		a = SumAtts(a, Att{"stroke": s.NextColour().String()})
+	}
+
+	// Diagrams in Column mode draw their first series by a marker
+	if s.seriesMarker(series[0]) != nil {
+		stroke, fill := fmt.Sprint(a["stroke"]), "none"
+		if v, ok := a["fill"]; ok {
+			fill = fmt.Sprint(v)
+		}
+		a = SumAtts(a, Att{"stroke": "none", "fill": "none"})
+
+		defs := plot.FindGroups("defs")
+		if len(defs) == 0 {
+			return nil, errors.New("Could not find defs of data to add column marker to")
+		}
+		a["marker-mid"] = URL(columnMarker(defs[0], stroke, fill))
+	}
+
 	line, err := plot.Polyline(d, SumAtts(Att{"vector-effect": "non-scaling-stroke"}, a))
 	if err != nil {
 		return nil, err
 	}
 	line.AddAtt(true, a)
+	s.addSeries(line)
 
 	return line, nil
 }
 
+// Marker of polyline in Column mode, or nil
+func (s *SVG) seriesMarker(line *SVG) *SVG {
+	ref, ok := line.a["marker-mid"]
+	if !ok {
+		return nil
+	}
+	id := strings.TrimSuffix(strings.TrimPrefix(fmt.Sprint(ref), "url(#"), ")")
+	return s.FindID(id)
+}
+
+// Set fill of every plot or series in diagram, in the order they were added. In Column mode, the fill is used for the columns.
+// Fills may be colours or references to patterns or gradients given by URL.
+func (s *SVG) SeriesFill(fills ...string) error {
+	if !s.isDiagram() {
+		return errors.New("Will only set fill of diagram")
+	}
+	data := s.series()
+	if len(data) != len(fills) {
+		return errors.New("Amount of plots found is not the same as the amount of fills given. #Data: " + fmt.Sprint(len(data)) + " #Fills: " + fmt.Sprint(len(fills)))
+	}
+	for i, line := range data {
+		if m := s.seriesMarker(line); m != nil {
+			// The stroke would cover the narrow column
+			m.a["fill"], m.a["stroke"] = fills[i], "none"
+		} else {
+			line.a["fill"] = fills[i]
+		}
+	}
+	return nil
+}
+
+// Paint used to draw plot, as shown in legend. Fill is preferred to stroke.
+func (s *SVG) seriesPaint(line *SVG) (string, error) {
+	g := line
+	if m := s.seriesMarker(line); m != nil {
+		g = m
+	}
+	for _, k := range []string{"fill", "stroke"} {
+		if v, ok := g.a[k]; ok {
+			if paint := fmt.Sprint(v); paint != "none" && paint != "" {
+				return paint, nil
+			}
+		}
+	}
+	return "", errors.New("Could not find fill or stroke colour")
+}
+
+// Add legend describing the series of diagram in the order they were added
 func (s *SVG) Legend(desc ...string) (*SVG, error) {
-	if s.a["id"] != "diagram" {
+	if !s.isDiagram() {
 		return nil, errors.New("Will only add legend to diagram")
 	}
-	data := s.FindGroups("polyline")
+	if s.a["class"] == "pie" {
+		return s.pieLegend(desc)
+	}
+	data := s.series()
 	if len(data) != len(desc) {
 		return nil, errors.New("Amount of plots found is not the same as the amount of descriptors given. #Data: " + fmt.Sprint(len(data)) + " #Desc: " + fmt.Sprint(len(desc)) + ". Desc is " + fmt.Sprint(desc))
 	}
 
 	var (
-		transform   string
 		pageHeight  int
 		lW, lH      int
-		p, n        int
 		titleHeight int
 	)
-	if plot := s.FindID("plot"); plot != nil {
-		if tr, ok := plot.a["transform"]; ok {
-			if transform, ok = tr.(string); !ok {
-				return nil, errors.New("Could not decode transform attribute to string")
-			}
-		} else {
+	if plot := s.part("plot"); plot != nil {
+		if _, ok := plot.a["transform"]; !ok {
 			return nil, errors.New("Could not find transform attribute of plot group")
 		}
+		t, err := plot.Transform()
+		if err != nil {
+			return nil, errors.New("Decode error: " + err.Error())
+		}
+		lW, titleHeight = int(t.E), int(t.F)
 	} else {
 		return nil, errors.New("Could not find plot group of diagram")
 	}
-	if h := s.a["height"]; h != nil {
-		var ok bool
-		if pageHeight, ok = h.(int); !ok {
-			return nil, errors.New("Could not fetch page height")
-		}
-	} else {
+	if _, ok := s.a["height"]; !ok {
 		return nil, errors.New("Could not find pageHeight attribute")
 	}
-
-	p = strings.Index(transform, "(")
-	n = strings.Index(transform, ",")
-	if p == -1 && n == -1 {
-		return nil, errors.New("Decode error: Could not find textWidth for translation of diagram. String searched: " + transform + ". Got p & n: " + fmt.Sprint(p, n))
-	} else {
-		var err error
-		lW, err = strconv.Atoi(transform[p+1 : n])
-		if err != nil {
-			return nil, err
-		}
-	}
-	p = strings.Index(transform, ")")
-	if p != -1 {
-		var err error
-		titleHeight, err = strconv.Atoi(transform[n+2 : p])
-		if err != nil {
-			return nil, err
-		}
-		lH = pageHeight - titleHeight
-	} else {
-		return nil, errors.New("Decode error: Could not find textHeight for translation of diagram. String searched: " + transform + "Got p & n: " + fmt.Sprint(p, n))
+	// Diagrams read by Parse have the height as text
+	h, err := s.num("height", 0)
+	if err != nil {
+		return nil, errors.New("Could not fetch page height")
 	}
+	pageHeight = int(h)
+	lH = pageHeight - titleHeight
 
 	textHeight := 10
 	legendMargin := 5
 	lW, lH = lW-2*legendMargin, lH-2*legendMargin
 	lW /= 3
-	legend := s.GID("legend", SumAtts(Translate(float64(legendMargin), float64(titleHeight+legendMargin)), ViewBox(0, 0, lW, lH)))
+	legend := s.GID("legend"+s.idSuffix(), SumAtts(Translate(float64(legendMargin), float64(titleHeight+legendMargin)), ViewBox(0, 0, lW, lH)))
 	def := legend.Def()
 
-	def.Rect(0, 0, lW, lH/len(data), Att{"id": "legendRect"})
+	rect := def.uniqueID("legendRect")
+	def.Rect(0, 0, lW, lH/len(data), Att{"id": rect})
 	yDiff := lH / len(data)
 	for i := 0; i < len(data); i++ {
-		if stroke, ok := data[i].a["stroke"]; ok {
-			if colour, ok := stroke.(string); ok {
-				legend.Use("legendRect", Att{"fill": colour, "y": yDiff * i})
-				t := legend.Text(textHeight/2+yDiff/2, yDiff*i, desc[i], Att{"text-anchor": "middle", "fill": "black"})
-				t.AddAtt(false, Att{"transform": fmt.Sprintf("rotate(90, %d, %d)", textHeight/2, yDiff*i)})
-			} else {
-				return nil, errors.New("Decode error: Could not find stroke colour for data at data element " + fmt.Sprint(i))
-			}
-		} else {
-			return nil, errors.New("Decode error: Could not find stroke attribute for data at data element " + fmt.Sprint(i))
+		// Series without paint are shown by their colour in the palette
+		colour, err := s.seriesPaint(data[i])
+		if err != nil {
+			colour = s.currentPalette().At(i).String()
 		}
+		legend.Use(rect, Att{"fill": colour, "y": yDiff * i})
+		t := legend.Text(textHeight/2+yDiff/2, yDiff*i, desc[i], Att{"text-anchor": "middle", "fill": "black"})
+		t.AddAtt(false, Att{"transform": Identity().RotateAbout(90, float64(textHeight/2), float64(yDiff*i))})
 	}
 	return legend, nil
 }
@@ -778,6 +901,15 @@ func (s *SVG) FindID(id string) *SVG {
 	return nil
 }
 
+// Id starting with prefix which no element of the document of s has yet
+func (s *SVG) uniqueID(prefix string) string {
+	id := prefix
+	for i := 2; s.root().FindID(id) != nil; i++ {
+		id = prefix + "-" + fmt.Sprint(i)
+	}
+	return id
+}
+
 // Search downwards for groups with matching tag
 func (s *SVG) FindGroups(tag string) (groups []*SVG) {
 	if s.tag == tag {
@@ -0,0 +1,103 @@
+package smartSVG_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	svg "github.com/tusj/go-svg"
+)
+
+var update = flag.Bool("update", false, "Update golden files in testdata")
+
+// Document using most kinds of content, drawn the same way every time
+func goldenDocument(t *testing.T) []byte {
+	s := svg.New(600, 800)
+	fade := s.Def().LinearGradient("fade", 0, 0, 1, 0, nil)
+	fade.AddStop(0, "white", 1)
+	fade.AddStop(1, "steelblue", 0.5)
+	d, err := s.Diagram(0, 0, 600, 400, svg.Data{X: []float64{0, 1, 2, 3, 4}, Y: []float64{1, 3, 2.5, 5, 4}}, "Lines", svg.Continuous)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.AddPlot(svg.Data{X: []float64{0, 1, 2, 3, 4}, Y: []float64{2, 1, 4, 3, 0.5}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.AddScatter(svg.Data{X: []float64{0.5, 1.5, 3.5}, Y: []float64{1, 2, 3}}, svg.ScatterOptions{Shape: svg.DiamondMarker, Jitter: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Legend("first", "second", "points"); err != nil {
+		t.Fatal(err)
+	}
+	bars, err := s.BarChart(0, 400, 600, 400, []string{"a", "b", "c"}, [][]float64{{1, -2, 3.25}, {2, 1, 0.5}}, "Bars", svg.BarOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bars.SeriesFill("url(#fade)", "tomato"); err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := s.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestGolden(t *testing.T) {
+	got := goldenDocument(t)
+	if again := goldenDocument(t); !bytes.Equal(got, again) {
+		t.Fatal("Same document was written differently twice")
+	}
+	golden := filepath.Join("testdata", "golden.svg")
+	if *update {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	doc, err := svg.Parse(bytes.NewReader(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues := doc.Validate(); len(issues) != 0 {
+		t.Errorf("Golden document has issues %v", issues)
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Output differs from %s, run go test -update if the change is intended:\n%s", golden, got)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	want := goldenDocument(t)
+	s, err := svg.Parse(bytes.NewReader(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	if err := s.Write(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Errorf("Parsed document is written as\n%s\nwant\n%s", got.Bytes(), want)
+	}
+}
+
+func TestLabel(t *testing.T) {
+	s := svg.New(100, 100)
+	s.Label(0, 100, 0, 0, []float64{1, 2}, 3, nil)
+	var b bytes.Buffer
+	if err := s.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	// Positions are rounded to whole pixels
+	for _, want := range []string{`y="100">1.00<`, `y="67">1.33<`, `y="33">1.67<`, `y="0">2.00<`} {
+		if !bytes.Contains(b.Bytes(), []byte(want)) {
+			t.Errorf("Labels have no %s:\n%s", want, b.Bytes())
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package smartSVG
+
+import (
+	"fmt"
+)
+
+// Values of gradientUnits and patternUnits
+const (
+	UserSpaceOnUse    = "userSpaceOnUse"
+	ObjectBoundingBox = "objectBoundingBox"
+)
+
+// Values of spreadMethod
+const (
+	SpreadPad     = "pad"
+	SpreadReflect = "reflect"
+	SpreadRepeat  = "repeat"
+)
+
+// Reference to element with id, to be used as fill or stroke
+func URL(id string) string {
+	return "url(#" + id + ")"
+}
+
+// Reference to s, to be used as fill or stroke. s must have an id.
+func (s *SVG) URL() string {
+	return URL(fmt.Sprint(s.a["id"]))
+}
+
+// Create linear gradient along the vector from x1, y1 to x2, y2. Should be placed inside defs.
+// Ref http://www.w3.org/TR/SVG11/pservers.html#LinearGradients
+func (s *SVG) LinearGradient(id string, x1, y1, x2, y2 float64, a Att) *SVG {
+	g := s.newGroup("linearGradient", a)
+	g.a["id"] = id
+	g.a["x1"] = x1
+	g.a["y1"] = y1
+	g.a["x2"] = x2
+	g.a["y2"] = y2
+	return g
+}
+
+// Create radial gradient on the circle at cx, cy with radius r and focal point fx, fy. Should be placed inside defs.
+// Ref http://www.w3.org/TR/SVG11/pservers.html#RadialGradients
+func (s *SVG) RadialGradient(id string, cx, cy, r, fx, fy float64, a Att) *SVG {
+	g := s.newGroup("radialGradient", a)
+	g.a["id"] = id
+	g.a["cx"] = cx
+	g.a["cy"] = cy
+	g.a["r"] = r
+	g.a["fx"] = fx
+	g.a["fy"] = fy
+	return g
+}
+
+// Add colour stop to gradient at offset between 0 and 1
+func (s *SVG) AddStop(offset float64, colour string, opacity float64) *SVG {
+	g := s.newGroup("stop", nil)
+	g.a["offset"] = offset
+	g.a["stop-color"] = colour
+	g.a["stop-opacity"] = opacity
+	return g
+}
+
+// Set coordinate system of gradient, UserSpaceOnUse or ObjectBoundingBox
+func (s *SVG) GradientUnits(units string) {
+	s.a["gradientUnits"] = units
+}
+
+// Set how gradient is continued outside its bounds, SpreadPad, SpreadReflect or SpreadRepeat
+func (s *SVG) SpreadMethod(method string) {
+	s.a["spreadMethod"] = method
+}
+
+// Set transform of gradient
+func (s *SVG) GradientTransform(transform Transform) {
+	s.a["gradientTransform"] = transform
+}
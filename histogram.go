@@ -0,0 +1,205 @@
+package smartSVG
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Rules choosing the width of bins of histograms, which is then rounded to 1, 2 or 5 times a power of ten.
+// Scott and FreedmanDiaconis fall back to Sturges when they would give more than 1000 bins.
+type Binning int
+
+const (
+	Sturges          Binning = iota // log2(n) + 1 bins, for roughly normal data
+	Scott                           // Bins of width 3.49 σ n^(-1/3)
+	FreedmanDiaconis                // Bins of width 2 IQR n^(-1/3), robust against outliers
+)
+
+// Heights of histogram bars
+type Normalisation int
+
+const (
+	Count      Normalisation = iota // Number of samples in bin
+	Density                         // Part of samples in bin per unit, so that the bars have a total area of 1
+	Cumulative                      // Part of samples in bin and the bins before it
+)
+
+// Options of Histogram. The zero value gives counts in bins chosen by Sturges' rule.
+type HistogramOptions struct {
+	Title         string
+	Binning       Binning
+	Edges         []float64 // Increasing edges of bins, used instead of Binning if set. Samples outside them are left out.
+	Normalisation Normalisation
+}
+
+// Largest number of bins chosen by Scott and FreedmanDiaconis
+const maxBins = 1000
+
+// Edges of bins covering sorted samples, as chosen by rule
+func binEdges(sorted []float64, rule Binning) ([]float64, error) {
+	n := float64(len(sorted))
+	lo, hi := sorted[0], sorted[len(sorted)-1]
+	if lo == hi {
+		return []float64{lo - 0.5, hi + 0.5}, nil
+	}
+	if math.IsInf(hi-lo, 0) {
+		return nil, errors.New("Samples span a range too wide for bins")
+	}
+
+	var width float64
+	switch rule {
+	case Sturges:
+	case Scott:
+		width = 3.49 * stdDev(sorted) * math.Cbrt(1/n)
+	case FreedmanDiaconis:
+		width = 2 * (quantile(sorted, 0.75) - quantile(sorted, 0.25)) * math.Cbrt(1/n)
+	default:
+		return nil, errors.New("Got unknown binning rule")
+	}
+	// Outliers may make the bins of Scott and Freedman-Diaconis too narrow to draw, so fall back to Sturges
+	if width <= 0 || (hi-lo)/width > maxBins {
+		width = (hi - lo) / (math.Ceil(math.Log2(n)) + 1)
+	}
+
+	// Snap width to the nearest of 1, 2 or 5 times a power of ten, with edges on whole multiples of it,
+	// so that the edges read well as labels
+	nice := niceStep(width)
+	if lower := niceStep(nice / 2.5); width/lower < nice/width {
+		nice = lower
+	}
+	width = nice
+	first := math.Floor(lo / width)
+	bins := math.Max(1, math.Ceil(hi/width)-first)
+	edges := make([]float64, int(bins)+1)
+	for i := range edges {
+		edges[i] = (first + float64(i)) * width
+	}
+	return edges, nil
+}
+
+// Number of decimals labelling all edges as they are, but at least as many as the narrowest bin needs
+func edgeDecimals(edges []float64, narrowest float64) int {
+	decimals := tickDecimals(narrowest)
+	for ; decimals < 10; decimals++ {
+		exact := true
+		for _, e := range edges {
+			v, _ := strconv.ParseFloat(Decimals(decimals).Format(e), 64)
+			exact = exact && math.Abs(v-e) <= 1e-9*narrowest
+		}
+		if exact {
+			break
+		}
+	}
+	return decimals
+}
+
+// Draw histogram of samples as adjacent bars, one per bin, with the edges of bins labelled below.
+// The chart works with Legend and SeriesFill like Diagram.
+func (s *SVG) Histogram(x, y, width, height int, samples []float64, opts HistogramOptions) (*SVG, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("Got no samples")
+	}
+	for _, v := range samples {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, errors.New("Sample " + fmt.Sprint(v) + " is not a number")
+		}
+	}
+	samples = sorted(samples)
+	edges := opts.Edges
+	if len(edges) == 0 {
+		var err error
+		if edges, err = binEdges(samples, opts.Binning); err != nil {
+			return nil, err
+		}
+	}
+	if len(edges) < 2 {
+		return nil, errors.New("Got less than two bin edges")
+	}
+	for i := 1; i < len(edges); i++ {
+		if edges[i] <= edges[i-1] {
+			return nil, errors.New("Bin edges are not increasing")
+		}
+	}
+
+	// Count samples of each bin. Bins include their lower edge, and the last one also its upper edge.
+	counts := make([]float64, len(edges)-1)
+	var total float64
+	for _, v := range samples {
+		if v < edges[0] || v > edges[len(edges)-1] {
+			continue
+		}
+		i := sort.SearchFloat64s(edges, v)
+		if edges[i] != v {
+			i--
+		}
+		if i == len(counts) {
+			i--
+		}
+		counts[i]++
+		total++
+	}
+	if total == 0 {
+		return nil, errors.New("Got no samples within the bins")
+	}
+
+	heights := make([]float64, len(counts))
+	var sum float64
+	for i, count := range counts {
+		switch opts.Normalisation {
+		case Count:
+			heights[i] = count
+		case Density:
+			heights[i] = count / total / (edges[i+1] - edges[i])
+		case Cumulative:
+			sum += count
+			heights[i] = sum / total
+		default:
+			return nil, errors.New("Got unknown normalisation")
+		}
+	}
+
+	c, err := s.newChart(x, y, width, height, opts.Title)
+	if err != nil {
+		return nil, err
+	}
+	ys := niceScale(0, max(heights...), c.height)
+	xs := scale{lo: edges[0], hi: edges[len(edges)-1], length: c.width}
+	c.valueAxis(ys, false)
+
+	// Label edges, leaving some out when they are too close
+	g := c.plot.G(Att{"class": "axis", "fill": "black", "stroke": "black"})
+	narrowest := xs.hi - xs.lo
+	for i := 1; i < len(edges); i++ {
+		narrowest = math.Min(narrowest, edges[i]-edges[i-1])
+	}
+	label := Decimals(edgeDecimals(edges, narrowest))
+	every := int(math.Ceil(float64(len(edges)) / (c.width / (4 * chartTextHeight))))
+	for i, e := range edges {
+		last := len(edges) - 1
+		if i != last && (i%every != 0 || last-i < every) {
+			continue
+		}
+		p := xs.pos(e)
+		g.LineF(p, c.height, p, c.height+3, nil)
+		g.TextF(p, c.height+chartTextHeight+4, label.Format(e), Att{"text-anchor": "middle", "stroke": "none"})
+	}
+
+	bars := c.series()
+	bars.a["stroke"] = "white"
+	bars.a["stroke-width"] = "0.5"
+	for i, h := range heights {
+		x1, x2 := xs.pos(edges[i]), xs.pos(edges[i+1])
+		top := c.valuePos(ys, h, false)
+		r := bars.RectF(x1, top, x2-x1, c.height-top, nil)
+		end := ")"
+		if i == len(heights)-1 {
+			end = "]"
+		}
+		r.Title("[" + exactFormat.Format(edges[i]) + ", " + exactFormat.Format(edges[i+1]) + end + ": " + exactFormat.Format(h))
+	}
+	c.frame()
+	return c.top, nil
+}
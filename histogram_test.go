@@ -0,0 +1,108 @@
+package smartSVG
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestBinEdges(t *testing.T) {
+	outlier := []float64{1e12}
+	for i := 0; i < 100; i++ {
+		outlier = append(outlier, float64(i)/100)
+	}
+	tests := []struct {
+		samples []float64
+		rule    Binning
+		want    []float64
+	}{
+		{[]float64{0, 1, 2, 3, 4, 5, 6, 7}, Sturges, []float64{0, 2, 4, 6, 8}},
+		{[]float64{2.5, 2.5}, Sturges, []float64{2, 3}},
+		{[]float64{0.3, 1.1, 2.4, 2.9, 3.3, 4.76, 7.1, 9.52, 11.9}, Sturges, []float64{0, 2, 4, 6, 8, 10, 12}},
+		{[]float64{-3, -1, 0, 2}, Scott, []float64{-5, 0, 5}},
+	}
+	for _, test := range tests {
+		got, err := binEdges(sorted(test.samples), test.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("binEdges(%v, %v) = %v, want %v", test.samples, test.rule, got, test.want)
+		}
+	}
+
+	// An outlier would make a huge number of narrow bins
+	for _, rule := range []Binning{Scott, FreedmanDiaconis} {
+		got, err := binEdges(sorted(outlier), rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) > maxBins+1 {
+			t.Errorf("binEdges with outlier by rule %v gave %v bins", rule, len(got)-1)
+		}
+	}
+	if _, err := binEdges([]float64{-math.MaxFloat64, math.MaxFloat64}, Sturges); err == nil {
+		t.Error("binEdges of samples spanning more than the largest float gave no error")
+	}
+}
+
+func TestHistogramLabels(t *testing.T) {
+	tests := []struct {
+		samples []float64
+		opts    HistogramOptions
+		want    []string
+	}{
+		{[]float64{0.3, 1.1, 2.4, 2.9, 3.3, 4.76, 7.1, 9.52, 11.9}, HistogramOptions{}, []string{"0", "2", "4", "6", "8", "10", "12"}},
+		{[]float64{1, 3}, HistogramOptions{Edges: []float64{0, 2.38, 4.76}}, []string{"0", "2.38", "4.76"}},
+		{[]float64{0.01, 0.02, 0.05, 0.07, 0.11, 0.13, 0.2}, HistogramOptions{Binning: Scott}, []string{"0", "0.1", "0.2"}},
+	}
+	labels := regexp.MustCompile(`<text stroke="none" text-anchor="middle" x="[^"]*" y="[^"]*">([^<]*)<`)
+	for _, test := range tests {
+		s := New(600, 400)
+		if _, err := s.Histogram(0, 0, 600, 400, test.samples, test.opts); err != nil {
+			t.Fatal(err)
+		}
+		var b bytes.Buffer
+		if err := s.Write(&b); err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, m := range labels.FindAllSubmatch(b.Bytes(), -1) {
+			got = append(got, string(m[1]))
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Labels of histogram of %v are %q, want %q", test.samples, got, test.want)
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	s := New(600, 400)
+	h, err := s.Histogram(0, 0, 600, 400, []float64{0, 1, 1, 2}, HistogramOptions{Edges: []float64{0, 1, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bars := h.series()
+	if len(bars) != 1 || len(bars[0].mids) != 2 {
+		t.Fatalf("Histogram has series %v, want one with two bars", bars)
+	}
+	// The last bin includes its upper edge
+	for i, want := range []string{"[0, 1): 1", "[1, 2]: 3"} {
+		if got := bars[0].mids[i].mids[0].data; got != want {
+			t.Errorf("Title of bar %v is %q, want %q", i, got, want)
+		}
+	}
+
+	for _, samples := range [][]float64{nil, {1, math.NaN()}, {1, math.Inf(1)}} {
+		if _, err := New(600, 400).Histogram(0, 0, 600, 400, samples, HistogramOptions{}); err == nil {
+			t.Errorf("Histogram of %v gave no error", samples)
+		}
+	}
+	for _, edges := range [][]float64{{1}, {0, 2, 1}, {5, 6}} {
+		if _, err := New(600, 400).Histogram(0, 0, 600, 400, []float64{1, 2}, HistogramOptions{Edges: edges}); err == nil {
+			t.Errorf("Histogram with edges %v gave no error", edges)
+		}
+	}
+}
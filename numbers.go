@@ -0,0 +1,127 @@
+package smartSVG
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Formatting of numbers in attributes, such as coordinates, points, path data, transforms and viewBox.
+// Numbers are never written with exponent.
+type NumberFormat struct {
+	Precision   int  // Number of decimals, or of significant digits if Significant is set. Negative for as many as needed to be exact.
+	Significant bool // Precision counts significant digits instead of decimals
+	KeepZeros   bool // Keep trailing zeros up to the precision, instead of trimming them
+}
+
+// Format used by documents unless another is set. Numbers are written exactly, in their shortest form.
+var defaultNumberFormat = NumberFormat{Precision: -1}
+
+// Format used by documents unless another is set with SetNumberFormat
+func DefaultNumberFormat() NumberFormat {
+	return defaultNumberFormat
+}
+
+// Format writing numbers exactly, used by String of values holding numbers so that they can be read back
+var exactFormat = NumberFormat{Precision: -1}
+
+// Format rounding to n decimals
+func Decimals(n int) NumberFormat {
+	return NumberFormat{Precision: n}
+}
+
+// Format rounding to n significant digits
+func SignificantDigits(n int) NumberFormat {
+	return NumberFormat{Precision: n, Significant: true}
+}
+
+// Format v as decimal number without exponent
+func (f NumberFormat) Format(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	decimals := f.Precision
+	switch {
+	case f.Significant && f.Precision <= 0:
+		decimals = -1
+	case f.Significant && v != 0:
+		// Round first, as rounding may change the magnitude, like 9.99 to 10
+		v, _ = strconv.ParseFloat(strconv.FormatFloat(v, 'e', f.Precision-1, 64), 64)
+		decimals = f.Precision - 1 - int(math.Floor(math.Log10(math.Abs(v))))
+		if decimals < 0 {
+			decimals = 0
+		}
+	case f.Significant:
+		decimals = f.Precision - 1
+	}
+	str := strconv.FormatFloat(v, 'f', decimals, 64)
+	if !f.KeepZeros && strings.Contains(str, ".") {
+		str = strings.TrimRight(strings.TrimRight(str, "0"), ".")
+	}
+
+	// Numbers rounded to zero have no sign
+	if strings.HasPrefix(str, "-") && strings.Trim(str, "-0.") == "" {
+		str = str[1:]
+	}
+	return str
+}
+
+// Values holding numbers, which are written with the format of the document
+type numberFormatter interface {
+	format(f NumberFormat) string
+}
+
+// Attribute value written with format f. Other values than numbers are written by fmt.
+func (f NumberFormat) value(v interface{}) string {
+	switch n := v.(type) {
+	case float64:
+		return f.Format(n)
+	case float32:
+		if f.Precision < 0 {
+			return strconv.FormatFloat(float64(n), 'f', -1, 32)
+		}
+		return f.Format(float64(n))
+	case numberFormatter:
+		return n.format(f)
+	}
+	return fmt.Sprint(v)
+}
+
+// List of numbers separated by spaces, as in viewBox, or of coordinate pairs x,y as in points
+type numberList struct {
+	values []float64
+	pairs  bool
+}
+
+func (l numberList) format(f NumberFormat) string {
+	strs := make([]string, 0, len(l.values))
+	for i := 0; i < len(l.values); i++ {
+		str := f.Format(l.values[i])
+		if l.pairs && i+1 < len(l.values) {
+			i++
+			str += "," + f.Format(l.values[i])
+		}
+		strs = append(strs, str)
+	}
+	return strings.Join(strs, " ")
+}
+
+func (l numberList) String() string {
+	return l.format(exactFormat)
+}
+
+// Set format of numbers written within s
+func (s *SVG) SetNumberFormat(f NumberFormat) {
+	s.numbers = &f
+}
+
+// Number format set on s or its closest ancestor
+func (s *SVG) numberFormat() NumberFormat {
+	for g := s; g != nil; g = g.parent {
+		if g.numbers != nil {
+			return *g.numbers
+		}
+	}
+	return defaultNumberFormat
+}
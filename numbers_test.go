@@ -0,0 +1,50 @@
+package smartSVG
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNumberFormat(t *testing.T) {
+	tests := []struct {
+		f    NumberFormat
+		v    float64
+		want string
+	}{
+		{defaultNumberFormat, 0.1, "0.1"},
+		{defaultNumberFormat, 1e21, "1000000000000000000000"},
+		{defaultNumberFormat, 1e-7, "0.0000001"},
+		{defaultNumberFormat, math.Inf(-1), "-Inf"},
+		{Decimals(2), 3.14159, "3.14"},
+		{Decimals(2), 2.5, "2.5"},
+		{Decimals(2), -0.001, "0"},
+		{Decimals(0), 7.6, "8"},
+		{NumberFormat{Precision: 2, KeepZeros: true}, 2.5, "2.50"},
+		{SignificantDigits(3), 123456, "123000"},
+		{SignificantDigits(3), 0.00123456, "0.00123"},
+		{SignificantDigits(2), 9.99, "10"},
+		{SignificantDigits(3), 0, "0"},
+		{NumberFormat{Precision: 3, Significant: true, KeepZeros: true}, 0, "0.00"},
+	}
+	for _, test := range tests {
+		if got := test.f.Format(test.v); got != test.want {
+			t.Errorf("Format of %v with %+v = %s, want %s", test.v, test.f, got, test.want)
+		}
+	}
+}
+
+func TestSetNumberFormat(t *testing.T) {
+	s := New(10, 10)
+	s.SetNumberFormat(Decimals(1))
+	g := s.G(nil)
+	g.LineF(0.123, 1.77, 2, 3, nil)
+	g.SetNumberFormat(Decimals(2))
+	s.G(nil).CircleF(1.234, 0, 1, nil)
+	got := s.String()
+	for _, want := range []string{`x1="0.12" x2="2" y1="1.77"`, `cx="1.2"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Document has no %s:\n%s", want, got)
+		}
+	}
+}
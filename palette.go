@@ -0,0 +1,190 @@
+package smartSVG
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Kinds of palettes
+type PaletteKind int
+
+const (
+	Categorical PaletteKind = iota // Distinct colours for unordered series
+	Sequential                     // Colours ordered from low to high
+	Diverging                      // Colours ordered from low through a neutral midpoint to high
+)
+
+// Ordered list of colours
+type Palette struct {
+	Name    string
+	Kind    PaletteKind
+	Colours []Colour
+}
+
+func newPalette(name string, kind PaletteKind, hex ...string) Palette {
+	p := Palette{Name: name, Kind: kind, Colours: make([]Colour, len(hex))}
+	for i, h := range hex {
+		c, err := ParseColour(h)
+		if err != nil {
+			panic(err)
+		}
+		p.Colours[i] = c
+	}
+	return p
+}
+
+// Built-in palettes. Categorical ones are from Tableau, ColorBrewer and Okabe and Ito, which is safe for colour blindness.
+// Sequential ones are sampled from matplotlib, diverging ones are from ColorBrewer.
+var (
+	Tableau10 = newPalette("Tableau10", Categorical,
+		"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac")
+	Set1 = newPalette("Set1", Categorical,
+		"#e41a1c", "#377eb8", "#4daf4a", "#984ea3", "#ff7f00", "#ffff33", "#a65628", "#f781bf", "#999999")
+	Dark2 = newPalette("Dark2", Categorical,
+		"#1b9e77", "#d95f02", "#7570b3", "#e7298a", "#66a61e", "#e6ab02", "#a6761d", "#666666")
+	OkabeIto = newPalette("OkabeIto", Categorical,
+		"#e69f00", "#56b4e9", "#009e73", "#f0e442", "#0072b2", "#d55e00", "#cc79a7", "#000000")
+	Viridis = newPalette("Viridis", Sequential,
+		"#440154", "#482475", "#414487", "#355f8d", "#2a788e", "#21918c", "#22a884", "#44bf70", "#7ad151", "#bddf26", "#fde725")
+	Magma = newPalette("Magma", Sequential,
+		"#000004", "#140e36", "#3b0f70", "#641a80", "#8c2981", "#b73779", "#de4968", "#f7705c", "#fe9f6d", "#fecf92", "#fcfdbf")
+	RdBu = newPalette("RdBu", Diverging,
+		"#67001f", "#b2182b", "#d6604d", "#f4a582", "#fddbc7", "#f7f7f7", "#d1e5f0", "#92c5de", "#4393c3", "#2166ac", "#053061")
+	BrBG = newPalette("BrBG", Diverging,
+		"#543005", "#8c510a", "#bf812d", "#dfc27d", "#f6e8c3", "#f5f5f5", "#c7eae5", "#80cdc1", "#35978f", "#01665e", "#003c30")
+)
+
+// Palette used by diagrams unless another is set, with colours of its own so that changes to Tableau10 do not reach it
+var defaultPalette = Tableau10.copy()
+
+// Palette used by diagrams unless another is set with SetPalette
+func DefaultPalette() Palette {
+	return defaultPalette.copy()
+}
+
+// Copy of p, not sharing its colours
+func (p Palette) copy() Palette {
+	p.Colours = append([]Colour(nil), p.Colours...)
+	return p
+}
+
+// Colour number i, repeating the palette when i passes its end
+func (p Palette) At(i int) Colour {
+	n := len(p.Colours)
+	return p.Colours[(i%n+n)%n]
+}
+
+// Colour at position t between 0 and 1, interpolated between neighbouring colours
+func (p Palette) Sample(t float64) Colour {
+	t = clamp(t) * float64(len(p.Colours)-1)
+	i := int(t)
+	if i >= len(p.Colours)-1 {
+		return p.Colours[len(p.Colours)-1]
+	}
+	return p.Colours[i].Mix(p.Colours[i+1], t-float64(i))
+}
+
+// n colours from the palette. Categorical palettes give their first n colours, repeating if needed,
+// while the others are sampled evenly from end to end.
+func (p Palette) Spread(n int) []Colour {
+	colours := make([]Colour, n)
+	for i := range colours {
+		switch {
+		case p.Kind == Categorical:
+			colours[i] = p.At(i)
+		case n == 1:
+			colours[i] = p.Sample(0.5)
+		default:
+			colours[i] = p.Sample(float64(i) / float64(n-1))
+		}
+	}
+	return colours
+}
+
+// Set palette of diagrams drawn within s from now on
+func (s *SVG) SetPalette(p Palette) {
+	p = p.copy()
+	s.palette = &p
+}
+
+// Palette set on s or its closest ancestor
+func (s *SVG) currentPalette() Palette {
+	for g := s; g != nil; g = g.parent {
+		if g.palette != nil {
+			return *g.palette
+		}
+	}
+	return defaultPalette
+}
+
+// Colour allocator of a document, seeded so that output is the same on every run. Safe for concurrent use.
+type colourAllocator struct {
+	mu   sync.Mutex
+	next int
+	rng  *rand.Rand
+}
+
+// Seed of allocators created on demand
+const defaultColourSeed = 1
+
+// Guards creation of allocators on demand
+var allocatorMu sync.Mutex
+
+// Start new colour allocation for s and its children, seeded by seed
+func (s *SVG) SetColourSeed(seed int64) {
+	allocatorMu.Lock()
+	defer allocatorMu.Unlock()
+	s.colours = &colourAllocator{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Allocator of s or its closest ancestor, created on the root if there is none
+func (s *SVG) allocator() *colourAllocator {
+	allocatorMu.Lock()
+	defer allocatorMu.Unlock()
+	g := s
+	for ; g.parent != nil; g = g.parent {
+		if g.colours != nil {
+			return g.colours
+		}
+	}
+	if g.colours == nil {
+		g.colours = &colourAllocator{rng: rand.New(rand.NewSource(defaultColourSeed))}
+	}
+	return g.colours
+}
+
+// Start colour allocation for s and its children, seeded from the allocation of its parent.
+// Used by diagrams, so that each diagram starts from the beginning of the palette.
+func (s *SVG) startColours() {
+	a := s.allocator()
+	a.mu.Lock()
+	seed := a.rng.Int63()
+	a.mu.Unlock()
+	s.SetColourSeed(seed)
+}
+
+// Next colour of the palette, counted per document or diagram
+func (s *SVG) NextColour() Colour {
+	a := s.allocator()
+	p := s.currentPalette()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.next++
+	return p.At(a.next - 1)
+}
+
+// Random opaque colour from the seeded generator of the document
+func (s *SVG) RandomColour() Colour {
+	a := s.allocator()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return RGBA(uint8(a.rng.Intn(256)), uint8(a.rng.Intn(256)), uint8(a.rng.Intn(256)), 1)
+}
+
+// Random number from 0 up to 1 from the seeded generator of the document
+func (s *SVG) random() float64 {
+	a := s.allocator()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.rng.Float64()
+}
@@ -0,0 +1,27 @@
+package smartSVG
+
+import "testing"
+
+func TestDefaultPalette(t *testing.T) {
+	p := DefaultPalette()
+	p.Colours[0] = Colour{}
+	second := Tableau10.Colours[1]
+	Tableau10.Colours[1] = Colour{}
+	defer func() { Tableau10.Colours[1] = second }()
+	if got := DefaultPalette().At(0).String(); got != "#4e79a7" {
+		t.Errorf("First colour of default palette is %s after changing a copy, want #4e79a7", got)
+	}
+	if got := New(10, 10).currentPalette().At(1).String(); got != "#f28e2b" {
+		t.Errorf("Second colour of default palette is %s after changing Tableau10, want #f28e2b", got)
+	}
+}
+
+func TestSpread(t *testing.T) {
+	p := Palette{Kind: Sequential, Colours: []Colour{{0, 0, 0, 1}, {1, 1, 1, 1}}}
+	want := []string{"#000000", "#808080", "#ffffff"}
+	for i, c := range p.Spread(3) {
+		if c.String() != want[i] {
+			t.Errorf("Colour %d of spread is %s, want %s", i, c, want[i])
+		}
+	}
+}
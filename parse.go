@@ -0,0 +1,108 @@
+package smartSVG
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Parse reads an svg document from r and builds the same tree as the one Write emits.
+// Processing instructions and directives before the root element are kept as the declaration,
+// comments are attached to the element following them and namespaces are kept as attributes.
+// Comments without a following element inside the same group are dropped, as Write cannot place them.
+// Text mixed with child elements, as in <text>a <tspan>b</tspan></text>, gives an error.
+func Parse(r io.Reader) (*SVG, error) {
+	dec := xml.NewDecoder(r)
+
+	var (
+		root        *SVG
+		stack       []*SVG
+		comments    []string
+		declaration string
+		text        []string
+	)
+
+	// Qualified name as written in the document, namespace prefix included
+	name := func(n xml.Name) string {
+		if n.Space != "" {
+			return n.Space + ":" + n.Local
+		}
+		return n.Local
+	}
+
+	for {
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.ProcInst:
+			if root == nil {
+				declaration += "<?" + t.Target + " " + string(t.Inst) + "?>\n"
+			}
+		case xml.Directive:
+			if root == nil {
+				declaration += "<!" + string(t) + ">\n"
+			}
+		case xml.Comment:
+			if root == nil || len(stack) != 0 {
+				comments = append(comments, string(t))
+			}
+		case xml.CharData:
+			if len(stack) != 0 {
+				text[len(text)-1] += string(t)
+			}
+		case xml.StartElement:
+			if root != nil && len(stack) == 0 {
+				return nil, errors.New("Found more than one root element")
+			}
+			g := &SVG{tag: name(t.Name), a: make(Att, len(t.Attr)), comments: comments}
+			comments = nil
+			for _, v := range t.Attr {
+				g.a[name(v.Name)] = v.Value
+			}
+			if root == nil {
+				if g.tag != "svg" {
+					return nil, errors.New("Root element is <" + g.tag + ">, not <svg>")
+				}
+				g.declaration = declaration
+				root = g
+			} else {
+				parent := stack[len(stack)-1]
+				g.parent = parent
+				parent.mids = append(parent.mids, g)
+			}
+			stack = append(stack, g)
+			text = append(text, "")
+		case xml.EndElement:
+			if len(stack) == 0 || stack[len(stack)-1].tag != name(t.Name) {
+				return nil, errors.New("Unexpected end element </" + name(t.Name) + ">")
+			}
+			g := stack[len(stack)-1]
+
+			// Text between child elements is only indentation written by Write. An element holds either data or
+			// children, so text mixed with children could not be kept.
+			if data := text[len(text)-1]; len(g.mids) == 0 {
+				g.data = data
+			} else if strings.TrimSpace(data) != "" {
+				return nil, errors.New("Found text mixed with child elements in <" + g.tag + ">, which is not supported")
+			}
+			comments = nil
+			stack = stack[:len(stack)-1]
+			text = text[:len(text)-1]
+		}
+	}
+
+	switch {
+	case root == nil:
+		return nil, errors.New("Found no svg element")
+	case len(stack) != 0:
+		return nil, errors.New("Unexpected end of document inside <" + stack[len(stack)-1].tag + ">")
+	}
+	return root, nil
+}
@@ -0,0 +1,422 @@
+package smartSVG
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// One command of path data. Command is the svg path letter, upper case for absolute and lower case for relative coordinates.
+type PathCommand struct {
+	Command byte
+	Args    []float64
+}
+
+// Path data as used by the d attribute of <path>. Ref http://www.w3.org/TR/SVG11/paths.html#PathData
+// The builder methods return the path data itself in order to chain calls.
+type PathData struct {
+	Commands []PathCommand
+}
+
+// Number of arguments taken by each path command
+var pathArgs = map[byte]int{
+	'M': 2, 'L': 2, 'H': 1, 'V': 1, 'C': 6, 'S': 4, 'Q': 4, 'T': 2, 'A': 7, 'Z': 0,
+}
+
+func NewPathData() *PathData {
+	return new(PathData)
+}
+
+func (p *PathData) add(cmd byte, args ...float64) *PathData {
+	p.Commands = append(p.Commands, PathCommand{cmd, args})
+	return p
+}
+
+func flag(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Start new subpath at x, y
+func (p *PathData) MoveTo(x, y float64) *PathData { return p.add('M', x, y) }
+
+// Start new subpath at dx, dy relative to current point
+func (p *PathData) MoveToRel(dx, dy float64) *PathData { return p.add('m', dx, dy) }
+
+// Draw line to x, y
+func (p *PathData) LineTo(x, y float64) *PathData { return p.add('L', x, y) }
+
+// Draw line to dx, dy relative to current point
+func (p *PathData) LineToRel(dx, dy float64) *PathData { return p.add('l', dx, dy) }
+
+// Draw horizontal line to x
+func (p *PathData) HLine(x float64) *PathData { return p.add('H', x) }
+
+// Draw horizontal line of length dx
+func (p *PathData) HLineRel(dx float64) *PathData { return p.add('h', dx) }
+
+// Draw vertical line to y
+func (p *PathData) VLine(y float64) *PathData { return p.add('V', y) }
+
+// Draw vertical line of length dy
+func (p *PathData) VLineRel(dy float64) *PathData { return p.add('v', dy) }
+
+// Draw cubic bézier curve to x, y with control points x1, y1 and x2, y2
+func (p *PathData) CubicTo(x1, y1, x2, y2, x, y float64) *PathData {
+	return p.add('C', x1, y1, x2, y2, x, y)
+}
+
+// Draw cubic bézier curve with all points relative to current point
+func (p *PathData) CubicToRel(dx1, dy1, dx2, dy2, dx, dy float64) *PathData {
+	return p.add('c', dx1, dy1, dx2, dy2, dx, dy)
+}
+
+// Draw cubic bézier curve to x, y where the first control point is the reflection of the previous one
+func (p *PathData) SmoothCubicTo(x2, y2, x, y float64) *PathData {
+	return p.add('S', x2, y2, x, y)
+}
+
+// Draw smooth cubic bézier curve with all points relative to current point
+func (p *PathData) SmoothCubicToRel(dx2, dy2, dx, dy float64) *PathData {
+	return p.add('s', dx2, dy2, dx, dy)
+}
+
+// Draw quadratic bézier curve to x, y with control point x1, y1
+func (p *PathData) QuadTo(x1, y1, x, y float64) *PathData {
+	return p.add('Q', x1, y1, x, y)
+}
+
+// Draw quadratic bézier curve with all points relative to current point
+func (p *PathData) QuadToRel(dx1, dy1, dx, dy float64) *PathData {
+	return p.add('q', dx1, dy1, dx, dy)
+}
+
+// Draw quadratic bézier curve to x, y where the control point is the reflection of the previous one
+func (p *PathData) SmoothQuadTo(x, y float64) *PathData { return p.add('T', x, y) }
+
+// Draw smooth quadratic bézier curve to dx, dy relative to current point
+func (p *PathData) SmoothQuadToRel(dx, dy float64) *PathData { return p.add('t', dx, dy) }
+
+// Draw elliptical arc to x, y. Ref http://www.w3.org/TR/SVG11/paths.html#PathDataEllipticalArcCommands
+func (p *PathData) ArcTo(rx, ry, rotation float64, largeArc, sweep bool, x, y float64) *PathData {
+	return p.add('A', rx, ry, rotation, flag(largeArc), flag(sweep), x, y)
+}
+
+// Draw elliptical arc to dx, dy relative to current point
+func (p *PathData) ArcToRel(rx, ry, rotation float64, largeArc, sweep bool, dx, dy float64) *PathData {
+	return p.add('a', rx, ry, rotation, flag(largeArc), flag(sweep), dx, dy)
+}
+
+// Close current subpath
+func (p *PathData) Close() *PathData { return p.add('Z') }
+
+// Encode path data to the form used in the d attribute
+func (p *PathData) String() string {
+	return p.format(exactFormat)
+}
+
+// Encode path data with numbers written by f
+func (p *PathData) format(f NumberFormat) string {
+	cmds := make([]string, len(p.Commands))
+	for i, c := range p.Commands {
+		args := make([]string, len(c.Args))
+		for j, v := range c.Args {
+			args[j] = f.Format(v)
+		}
+
+		// Pair coordinates as x,y
+		var str string
+		switch len(args) {
+		case 0, 1:
+			str = strings.Join(args, "")
+		case 7:
+			str = args[0] + "," + args[1] + " " + args[2] + " " + args[3] + "," + args[4] + " " + args[5] + "," + args[6]
+		default:
+			pairs := make([]string, 0, len(args)/2)
+			for j := 0; j+1 < len(args); j += 2 {
+				pairs = append(pairs, args[j]+","+args[j+1])
+			}
+			str = strings.Join(pairs, " ")
+		}
+		cmds[i] = string(c.Command) + str
+	}
+	return strings.Join(cmds, " ")
+}
+
+// Parse path data from the d attribute of a path
+func ParsePathData(d string) (*PathData, error) {
+	p := NewPathData()
+	i := 0
+
+	skip := func() {
+		for i < len(d) && (d[i] == ' ' || d[i] == ',' || d[i] == '\t' || d[i] == '\n' || d[i] == '\r') {
+			i++
+		}
+	}
+	number := func() (float64, error) {
+		skip()
+		start := i
+		if i < len(d) && (d[i] == '+' || d[i] == '-') {
+			i++
+		}
+		dot, exp := false, false
+		for ; i < len(d); i++ {
+			c := d[i]
+			switch {
+			case c >= '0' && c <= '9':
+			case c == '.' && !dot && !exp:
+				dot = true
+			case (c == 'e' || c == 'E') && !exp && i > start:
+				exp = true
+				if i+1 < len(d) && (d[i+1] == '+' || d[i+1] == '-') {
+					i++
+				}
+			default:
+				return strconv.ParseFloat(d[start:i], 64)
+			}
+		}
+		return strconv.ParseFloat(d[start:i], 64)
+	}
+	// Arc flags are single digits which need no separator
+	arcFlag := func() (float64, error) {
+		skip()
+		if i < len(d) && (d[i] == '0' || d[i] == '1') {
+			i++
+			return float64(d[i-1] - '0'), nil
+		}
+		return 0, errors.New("Expected arc flag at position " + fmt.Sprint(i) + " in path data")
+	}
+
+	var cmd byte
+	for skip(); i < len(d); skip() {
+		c := d[i]
+		if _, ok := pathArgs[upper(c)]; ok {
+			cmd = c
+			i++
+		} else if cmd == 0 {
+			return nil, errors.New("Path data does not start with a command: " + d)
+		} else if upper(cmd) == 'Z' {
+			return nil, errors.New("Unexpected number after close command at position " + fmt.Sprint(i) + " in path data")
+		}
+
+		n := pathArgs[upper(cmd)]
+		args := make([]float64, n)
+		for j := range args {
+			var err error
+			if upper(cmd) == 'A' && (j == 3 || j == 4) {
+				args[j], err = arcFlag()
+			} else {
+				args[j], err = number()
+			}
+			if err != nil {
+				return nil, errors.New("Could not parse argument " + fmt.Sprint(j+1) + " of command " + string(cmd) + ": " + err.Error())
+			}
+		}
+		p.add(cmd, args...)
+
+		// Coordinate pairs repeated after moveto are implicit lineto commands
+		switch cmd {
+		case 'M':
+			cmd = 'L'
+		case 'm':
+			cmd = 'l'
+		}
+	}
+	return p, nil
+}
+
+func upper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// Draw path. Nil path data gives an empty path. Ref http://www.w3.org/TR/SVG11/paths.html#PathElement
+func (s *SVG) Path(d *PathData, a Att) *SVG {
+	if d == nil {
+		d = NewPathData()
+	}
+	g := s.newGroup("path", a)
+	g.a["d"] = d
+	return g
+}
+
+// Get path data of path. Path data read from files is parsed and replaces the d attribute,
+// so that changes to the returned path data are written.
+func (s *SVG) PathData() (*PathData, error) {
+	p, err := s.pathData()
+	if err != nil {
+		return nil, err
+	}
+	s.a["d"] = p
+	return p, nil
+}
+
+// Path data of path for reading, leaving the d attribute as it is
+func (s *SVG) pathData() (*PathData, error) {
+	switch d := s.a["d"].(type) {
+	case *PathData:
+		return d, nil
+	case nil:
+		return nil, errors.New("Group has no path data")
+	default:
+		return ParsePathData(fmt.Sprint(d))
+	}
+}
+
+// Return equivalent path data using only absolute M, L, C and Z commands.
+// Quadratic curves and arcs are converted to cubic curves.
+func (p *PathData) Normalize() *PathData {
+	n := NewPathData()
+	var (
+		x, y           float64 // Current point
+		startX, startY float64 // Start of subpath
+		ctrlX, ctrlY   float64 // Last control point, for smooth curves
+		prev           byte
+	)
+	for _, c := range p.Commands {
+		args := make([]float64, len(c.Args))
+		copy(args, c.Args)
+		cmd := upper(c.Command)
+
+		// Make coordinates absolute
+		if c.Command != cmd {
+			switch cmd {
+			case 'H':
+				args[0] += x
+			case 'V':
+				args[0] += y
+			case 'A':
+				args[5] += x
+				args[6] += y
+			default:
+				for i := 0; i+1 < len(args); i += 2 {
+					args[i] += x
+					args[i+1] += y
+				}
+			}
+		}
+
+		// Reflected control point of smooth curves
+		reflect := func(curves string) (float64, float64) {
+			if strings.IndexByte(curves, prev) != -1 {
+				return 2*x - ctrlX, 2*y - ctrlY
+			}
+			return x, y
+		}
+		quad := func(qx, qy, ex, ey float64) {
+			n.CubicTo(x+2.0/3*(qx-x), y+2.0/3*(qy-y), ex+2.0/3*(qx-ex), ey+2.0/3*(qy-ey), ex, ey)
+			ctrlX, ctrlY = qx, qy
+			x, y = ex, ey
+		}
+
+		switch cmd {
+		case 'M':
+			n.MoveTo(args[0], args[1])
+			x, y = args[0], args[1]
+			startX, startY = x, y
+		case 'L':
+			n.LineTo(args[0], args[1])
+			x, y = args[0], args[1]
+		case 'H':
+			n.LineTo(args[0], y)
+			x = args[0]
+		case 'V':
+			n.LineTo(x, args[0])
+			y = args[0]
+		case 'C':
+			n.CubicTo(args[0], args[1], args[2], args[3], args[4], args[5])
+			ctrlX, ctrlY = args[2], args[3]
+			x, y = args[4], args[5]
+		case 'S':
+			x1, y1 := reflect("CS")
+			n.CubicTo(x1, y1, args[0], args[1], args[2], args[3])
+			ctrlX, ctrlY = args[0], args[1]
+			x, y = args[2], args[3]
+		case 'Q':
+			quad(args[0], args[1], args[2], args[3])
+		case 'T':
+			qx, qy := reflect("QT")
+			quad(qx, qy, args[0], args[1])
+		case 'A':
+			arcToCubics(n, x, y, args[0], args[1], args[2], args[3] != 0, args[4] != 0, args[5], args[6])
+			x, y = args[5], args[6]
+		case 'Z':
+			n.Close()
+			x, y = startX, startY
+		}
+		prev = cmd
+	}
+	return n
+}
+
+// Append elliptical arc from x1, y1 to x2, y2 as cubic curves.
+// Ref http://www.w3.org/TR/SVG11/implnote.html#ArcImplementationNotes
+func arcToCubics(p *PathData, x1, y1, rx, ry, rotation float64, largeArc, sweep bool, x2, y2 float64) {
+	if x1 == x2 && y1 == y2 {
+		return
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		p.LineTo(x2, y2)
+		return
+	}
+
+	sin, cos := math.Sincos(rotation * math.Pi / 180)
+	dx, dy := (x1-x2)/2, (y1-y2)/2
+	x1p, y1p := cos*dx+sin*dy, -sin*dx+cos*dy
+
+	// Scale up radii too small to reach end point
+	if l := x1p*x1p/(rx*rx) + y1p*y1p/(ry*ry); l > 1 {
+		rx, ry = rx*math.Sqrt(l), ry*math.Sqrt(l)
+	}
+
+	num := rx*rx*ry*ry - rx*rx*y1p*y1p - ry*ry*x1p*x1p
+	den := rx*rx*y1p*y1p + ry*ry*x1p*x1p
+	coef := math.Sqrt(math.Max(0, num/den))
+	if largeArc == sweep {
+		coef = -coef
+	}
+	cxp, cyp := coef*rx*y1p/ry, -coef*ry*x1p/rx
+	cx := cos*cxp - sin*cyp + (x1+x2)/2
+	cy := sin*cxp + cos*cyp + (y1+y2)/2
+
+	angle := func(ux, uy, vx, vy float64) float64 {
+		return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+	}
+	theta := angle(1, 0, (x1p-cxp)/rx, (y1p-cyp)/ry)
+	delta := angle((x1p-cxp)/rx, (y1p-cyp)/ry, (-x1p-cxp)/rx, (-y1p-cyp)/ry)
+	switch {
+	case !sweep && delta > 0:
+		delta -= 2 * math.Pi
+	case sweep && delta < 0:
+		delta += 2 * math.Pi
+	}
+
+	// Map point on unit circle to ellipse
+	point := func(ux, uy float64) (float64, float64) {
+		return cx + rx*ux*cos - ry*uy*sin, cy + rx*ux*sin + ry*uy*cos
+	}
+
+	// One cubic curve for each quarter of the ellipse
+	segments := int(math.Ceil(math.Abs(delta) / (math.Pi / 2)))
+	d := delta / float64(segments)
+	k := 4.0 / 3 * math.Tan(d/4)
+	for i := 0; i < segments; i++ {
+		t1, t2 := theta+float64(i)*d, theta+float64(i+1)*d
+		s1, c1 := math.Sincos(t1)
+		s2, c2 := math.Sincos(t2)
+		ax, ay := point(c1-k*s1, s1+k*c1)
+		bx, by := point(c2+k*s2, s2-k*c2)
+		ex, ey := point(c2, s2)
+		if i == segments-1 {
+			ex, ey = x2, y2
+		}
+		p.CubicTo(ax, ay, bx, by, ex, ey)
+	}
+}
@@ -0,0 +1,40 @@
+package smartSVG
+
+import "testing"
+
+func TestParsePathData(t *testing.T) {
+	tests := []struct {
+		d, want string // want is empty if d is invalid
+	}{
+		{"", ""},
+		{"M10 20L30 40Z", "M10,20 L30,40 Z"},
+		{"m 1,2 3,4 5,6", "m1,2 l3,4 l5,6"},
+		{"M1 2 3 4", "M1,2 L3,4"},
+		{"M-1.5-2.5", "M-1.5,-2.5"},
+		{"M.5.5", "M0.5,0.5"},
+		{"M1e2,-2E-1", "M100,-0.2"},
+		{"M0 0H10V-10h5v5", "M0,0 H10 V-10 h5 v5"},
+		{"M0 0C1 2 3 4 5 6S7 8 9 10", "M0,0 C1,2 3,4 5,6 S7,8 9,10"},
+		{"M0 0Q1 2 3 4T5 6", "M0,0 Q1,2 3,4 T5,6"},
+		{"M0 0A5 5 30 1 0 10 10", "M0,0 A5,5 30 1,0 10,10"},
+		{"M0 0a5 5 0 1110 10", "M0,0 a5,5 0 1,1 10,10"},
+		{"\tM 0,0\n L 1 , 1 ", "M0,0 L1,1"},
+		{"M1", ""},
+		{"M1 2 Z 3", ""},
+		{"M0 0A5 5 0 2 0 1 1", ""},
+		{"M0 0X1", ""},
+	}
+	for _, test := range tests {
+		p, err := ParsePathData(test.d)
+		switch {
+		case test.want == "" && test.d != "":
+			if err == nil {
+				t.Errorf("ParsePathData(%q) = %q, want error", test.d, p)
+			}
+		case err != nil:
+			t.Errorf("ParsePathData(%q) gave error %v", test.d, err)
+		case p.String() != test.want:
+			t.Errorf("ParsePathData(%q) = %q, want %q", test.d, p, test.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package smartSVG
+
+import "errors"
+
+// Ready-made fill patterns
+const (
+	HorizontalHatch = iota
+	VerticalHatch
+	DiagonalHatch
+	Crosshatch
+	Dots
+)
+
+// Create pattern tile of given size. Should be placed inside defs.
+// The tile is given in user space unless patternUnits is set in a.
+// Ref http://www.w3.org/TR/SVG11/pservers.html#Patterns
+func (s *SVG) Pattern(id string, x, y, width, height float64, a Att) *SVG {
+	g := s.newGroup("pattern", a)
+	g.a["id"] = id
+	g.a["x"] = x
+	g.a["y"] = y
+	g.a["width"] = width
+	g.a["height"] = height
+	if _, ok := g.a["patternUnits"]; !ok {
+		g.a["patternUnits"] = UserSpaceOnUse
+	}
+	return g
+}
+
+// Create ready-made pattern repeating every spacing units, drawn with lines or dots of size strokeWidth.
+// Use URL(id) as fill to paint with the pattern. Should be placed inside defs.
+func (s *SVG) FillPattern(id string, style int, spacing, strokeWidth float64, colour string) (*SVG, error) {
+	switch {
+	case style < HorizontalHatch || style > Dots:
+		return nil, errors.New("Got unknown pattern style")
+	case spacing <= 0:
+		return nil, errors.New("Pattern spacing must be positive")
+	}
+	mid := spacing / 2
+	line := Att{"stroke": colour, "stroke-width": strokeWidth}
+
+	g := s.Pattern(id, 0, 0, spacing, spacing, nil)
+	switch style {
+	case HorizontalHatch:
+		g.LineF(0, mid, spacing, mid, line)
+	case VerticalHatch:
+		g.LineF(mid, 0, mid, spacing, line)
+	case DiagonalHatch:
+		g.a["patternTransform"] = Identity().Rotate(45)
+		g.LineF(mid, 0, mid, spacing, line)
+	case Crosshatch:
+		g.LineF(0, mid, spacing, mid, line)
+		g.LineF(mid, 0, mid, spacing, line)
+	case Dots:
+		g.CircleF(mid, mid, strokeWidth, Att{"fill": colour, "stroke": "none"})
+	}
+	return g, nil
+}
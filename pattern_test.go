@@ -0,0 +1,72 @@
+package smartSVG
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// Number of pixels of img with colour c
+func countColour(img *image.RGBA, c color.Color) (n int) {
+	want := color.RGBAModel.Convert(c).(color.RGBA)
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if img.RGBAAt(x, y) == want {
+				n++
+			}
+		}
+	}
+	return
+}
+
+func TestFillPattern(t *testing.T) {
+	s := New(40, 40)
+	if _, err := s.Def().FillPattern("hatch", HorizontalHatch, 10, 4, "red"); err != nil {
+		t.Fatal(err)
+	}
+	s.Rect(0, 0, 40, 40, Att{"fill": URL("hatch")})
+	img, err := s.Render(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	red, _ := ParseColour("red")
+	// Lines 4 wide every 10 units cover 40 % of the rect
+	if n := countColour(img, red); n < 500 || n > 700 {
+		t.Errorf("Hatched rect has %v red pixels, want 640", n)
+	}
+
+	for _, test := range []struct {
+		style   int
+		spacing float64
+	}{{-1, 10}, {Dots + 1, 10}, {Dots, 0}} {
+		if _, err := New(10, 10).Def().FillPattern("p", test.style, test.spacing, 1, "red"); err == nil {
+			t.Errorf("FillPattern with style %v and spacing %v gave no error", test.style, test.spacing)
+		}
+	}
+}
+
+func TestSeriesFillColumns(t *testing.T) {
+	s := New(400, 300)
+	d, err := s.Diagram(0, 0, 400, 300, Data{[]float64{0, 1, 2, 3, 4}, []float64{1, 3, 2, 5, 4}}, "Columns", Column)
+	if err != nil {
+		t.Fatal(err)
+	}
+	series, _ := ParseColour(fmt.Sprint(d.seriesMarker(d.series()[0]).a["stroke"]))
+	if err := d.SeriesFill("#00ff00"); err != nil {
+		t.Fatal(err)
+	}
+	img, err := s.Render(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Columns are painted by the fill only, as the stroke would cover them
+	green, _ := ParseColour("#00ff00")
+	if n := countColour(img, green); n == 0 {
+		t.Error("Columns do not show their fill")
+	}
+	if n := countColour(img, series); n != 0 {
+		t.Errorf("Columns have %v pixels of the colour of their stroke", n)
+	}
+}
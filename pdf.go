@@ -0,0 +1,531 @@
+package smartSVG
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Objects of the PDF with fixed numbers. Patterns are added after these.
+const (
+	pdfCatalog = iota + 1
+	pdfPages
+	pdfPage
+	pdfContents
+	pdfFont
+	pdfResources
+)
+
+// Page of the pdf being written
+type pdfWriter struct {
+	out        *bytes.Buffer // Content stream being written
+	objects    []string      // Bodies of objects after the fixed ones
+	gStates    map[[2]float64]string
+	gStateDefs []string
+	patterns   []string      // Resource definitions of patterns
+	scale      float64       // Page units per root user unit, used for strokes which do not scale
+	active     map[*SVG]bool // Groups referenced by use or paint being drawn, to break cycles
+}
+
+// Format number for pdf, which does not allow exponents
+func pdfNum(v float64) string {
+	v = math.Round(v*1e4) / 1e4
+	if v == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func pdfMatrix(t Transform) string {
+	return strings.Join([]string{pdfNum(t.A), pdfNum(t.B), pdfNum(t.C), pdfNum(t.D), pdfNum(t.E), pdfNum(t.F)}, " ")
+}
+
+// Write text as pdf string in WinAnsi encoding. Characters outside Latin-1 are written as '?'.
+func pdfString(text string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= ' ' && r <= '~':
+			b.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// Write normalized path data mapped through t
+func (p *pdfWriter) path(n *PathData, t Transform) {
+	for _, c := range n.Commands {
+		var args []string
+		for i := 0; i+1 < len(c.Args); i += 2 {
+			x, y := t.Apply(c.Args[i], c.Args[i+1])
+			args = append(args, pdfNum(x), pdfNum(y))
+		}
+		op := map[byte]string{'M': "m", 'L': "l", 'C': "c", 'Z': "h"}[c.Command]
+		p.out.WriteString(strings.Join(append(args, op), " ") + "\n")
+	}
+}
+
+// Clip to rectangle x, y, width, height mapped through t
+func (p *pdfWriter) clipRect(t Transform, x, y, width, height float64) {
+	p.path(NewPathData().MoveTo(x, y).HLine(x+width).VLine(y+height).HLine(x).Close().Normalize(), t)
+	p.out.WriteString("W n\n")
+}
+
+// Name of graphics state with fill alpha and stroke alpha
+func (p *pdfWriter) gState(fill, stroke float64) string {
+	key := [2]float64{fill, stroke}
+	if name, ok := p.gStates[key]; ok {
+		return name
+	}
+	name := "GS" + strconv.Itoa(len(p.gStateDefs))
+	p.gStates[key] = name
+	p.gStateDefs = append(p.gStateDefs, "/"+name+" << /ca "+pdfNum(fill)+" /CA "+pdfNum(stroke)+" >>")
+	return name
+}
+
+// Add pattern object and return its resource name
+func (p *pdfWriter) pattern(body string) string {
+	name := "P" + strconv.Itoa(len(p.patterns))
+	p.objects = append(p.objects, body)
+	p.patterns = append(p.patterns, fmt.Sprintf("/%s %d 0 R", name, pdfResources+len(p.objects)))
+	return name
+}
+
+// Colour operator setting paint of element s, for fill or for stroke. ctm maps user space to the stream space.
+// Returns the alpha to apply, and false if there is no paint.
+func (p *pdfWriter) paint(s *SVG, value string, stroke bool, ctm Transform, box bounds) (op string, alpha float64, ok bool) {
+	colourOp, patternOp := "rg", "/Pattern cs /%s scn"
+	if stroke {
+		colourOp, patternOp = "RG", "/Pattern CS /%s SCN"
+	}
+	if value == "none" || value == "" {
+		return "", 0, false
+	}
+	if strings.HasPrefix(value, "url(") {
+		if ref := s.reference(value); ref != nil {
+			var name string
+			switch ref.tag {
+			case "linearGradient", "radialGradient":
+				name, alpha = p.gradient(ref, ctm, box)
+			case "pattern":
+				name, alpha = p.tile(ref, ctm, box)
+			}
+			if name != "" {
+				return fmt.Sprintf(patternOp, name), alpha, true
+			}
+		}
+		// Fallback colour after the reference
+		value = strings.TrimSpace(value[strings.Index(value, ")")+1:])
+	}
+	if value == "currentColor" {
+		value = "black"
+		if v, ok := s.inherited("color"); ok {
+			value = fmt.Sprint(v)
+		}
+	}
+	r, g, b, a, err := parseColour(value)
+	if err != nil || a == 0 {
+		return "", 0, false
+	}
+	return strings.Join([]string{pdfNum(r / a), pdfNum(g / a), pdfNum(b / a), colourOp}, " "), a, true
+}
+
+// Add shading pattern of gradient g. PDF shadings carry no alpha, so only opacity common to all stops is kept,
+// and reflected and repeated gradients are padded.
+func (p *pdfWriter) gradient(g *SVG, ctm Transform, box bounds) (string, float64) {
+	stops := g.gradientStops()
+	gt, ok := g.gradientSpace(box)
+	if len(stops) == 0 || !ok {
+		return "", 0
+	}
+	alpha := stops[0].a
+	colour := func(s gradientStop) string {
+		if s.a != alpha {
+			alpha = 1
+		}
+		if s.a == 0 {
+			return "[0 0 0]"
+		}
+		return "[" + pdfNum(s.r/s.a) + " " + pdfNum(s.g/s.a) + " " + pdfNum(s.b/s.a) + "]"
+	}
+
+	// Stitch one interpolation per pair of stops, padding to offsets 0 and 1
+	if stops[0].offset > 0 {
+		stops = append([]gradientStop{stops[0]}, stops...)
+		stops[0].offset = 0
+	}
+	if last := stops[len(stops)-1]; last.offset < 1 {
+		last.offset = 1
+		stops = append(stops, last)
+	}
+	var funcs, limits, encode []string
+	for i := 0; i+1 < len(stops); i++ {
+		funcs = append(funcs, "<< /FunctionType 2 /Domain [0 1] /C0 "+colour(stops[i])+" /C1 "+colour(stops[i+1])+" /N 1 >>")
+		encode = append(encode, "0 1")
+		if i > 0 {
+			limits = append(limits, pdfNum(stops[i].offset))
+		}
+	}
+	function := funcs[0]
+	if len(funcs) > 1 {
+		function = "<< /FunctionType 3 /Domain [0 1] /Functions [" + strings.Join(funcs, " ") +
+			"] /Bounds [" + strings.Join(limits, " ") + "] /Encode [" + strings.Join(encode, " ") + "] >>"
+	}
+
+	coord := g.gradientCoord
+	var shading string
+	if g.tag == "linearGradient" {
+		shading = "/ShadingType 2 /Coords [" + strings.Join([]string{
+			pdfNum(coord("x1", 0)), pdfNum(coord("y1", 0)), pdfNum(coord("x2", 1)), pdfNum(coord("y2", 0))}, " ") + "]"
+	} else {
+		cx, cy := coord("cx", 0.5), coord("cy", 0.5)
+		shading = "/ShadingType 3 /Coords [" + strings.Join([]string{
+			pdfNum(coord("fx", cx)), pdfNum(coord("fy", cy)), "0", pdfNum(cx), pdfNum(cy), pdfNum(coord("r", 0.5))}, " ") + "]"
+	}
+	return p.pattern("<< /Type /Pattern /PatternType 2 /Matrix [" + pdfMatrix(ctm.Multiply(gt)) +
+		"] /Shading << " + shading + " /ColorSpace /DeviceRGB /Function " + function + " /Extend [true true] >> >>"), alpha
+}
+
+// Add tiling pattern drawing the content of pattern element pat
+func (p *pdfWriter) tile(pat *SVG, ctm Transform, box bounds) (string, float64) {
+	vals, err := pat.nums("x", "y", "width", "height")
+	if err != nil || vals[2] <= 0 || vals[3] <= 0 || p.active[pat] {
+		return "", 0
+	}
+	if units, _ := pat.property("patternUnits"); units != UserSpaceOnUse {
+		if !box.ok {
+			return "", 0
+		}
+		bt := boxTransform(box)
+		vals[0], vals[1] = bt.Apply(vals[0], vals[1])
+		vals[2], vals[3] = vals[2]*bt.A, vals[3]*bt.D
+	}
+	pt := ctm
+	if v, ok := pat.a["patternTransform"]; ok {
+		if tr, err := toTransform(v); err == nil {
+			pt = pt.Multiply(tr)
+		}
+	}
+
+	// Draw the tile into its own stream
+	p.active[pat] = true
+	defer delete(p.active, pat)
+	out := p.out
+	p.out = new(bytes.Buffer)
+	err = p.children(pat, Identity().Translate(-vals[0], -vals[1]), ancestorStyle(pat))
+	content := p.out
+	p.out = out
+	if err != nil {
+		return "", 0
+	}
+	return p.pattern("<< /Type /Pattern /PatternType 1 /PaintType 1 /TilingType 1 /BBox [0 0 " +
+		pdfNum(vals[2]) + " " + pdfNum(vals[3]) + "] /XStep " + pdfNum(vals[2]) + " /YStep " + pdfNum(vals[3]) +
+		" /Matrix [" + pdfMatrix(pt.Translate(vals[0], vals[1])) + "] /Resources " + strconv.Itoa(pdfResources) + " 0 R" +
+		pdfStream(content.Bytes())), 1
+}
+
+// Dictionary entries and data of compressed stream, following the entries of its dictionary
+func pdfStream(data []byte) string {
+	var b bytes.Buffer
+	z := zlib.NewWriter(&b)
+	z.Write(data)
+	z.Close()
+	return " /Filter /FlateDecode /Length " + strconv.Itoa(b.Len()) + " >>\nstream\n" + b.String() + "\nendstream"
+}
+
+func (p *pdfWriter) children(s *SVG, ctm Transform, st style) error {
+	for _, c := range s.mids {
+		if err := p.draw(c, ctm, st); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Draw s, where ctm maps the user space of the parent to the stream space
+func (p *pdfWriter) draw(s *SVG, ctm Transform, st style) error {
+	if v, ok := s.property("display"); (ok && v == "none") || notRendered[s.tag] {
+		return nil
+	}
+	st = st.inherit(s)
+
+	if s.tag == "svg" {
+		vp, err := s.viewport(0, 0)
+		if err != nil {
+			return err
+		}
+		// Nested svg clips its content to its viewport
+		p.out.WriteString("q\n")
+		if vals, err := s.nums("x", "y", "width", "height"); err == nil && vals[2] > 0 && vals[3] > 0 {
+			p.clipRect(ctm, vals[0], vals[1], vals[2], vals[3])
+		}
+		err = p.children(s, ctm.Multiply(vp), st)
+		p.out.WriteString("Q\n")
+		return err
+	}
+
+	own, err := s.Transform()
+	if err != nil {
+		return err
+	}
+	ctm = ctm.Multiply(own)
+
+	p.out.WriteString("q\n")
+	defer p.out.WriteString("Q\n")
+	if ref := s.reference(propertyOr(s, "clip-path", "")); ref != nil && ref.tag == "clipPath" {
+		if err := p.clipPath(s, ref, ctm); err != nil {
+			return err
+		}
+	}
+
+	switch s.tag {
+	case "path", "rect", "circle", "ellipse", "line", "polyline", "polygon":
+		return p.shape(s, ctm, st)
+	case "text":
+		return p.text(s, ctm, st)
+	case "use":
+		ref := s.href()
+		if ref == nil || p.active[ref] || (notRendered[ref.tag] && ref.tag != "symbol") {
+			return nil
+		}
+		vals, err := s.nums("x", "y", "width", "height")
+		if err != nil {
+			return err
+		}
+		p.active[ref] = true
+		defer delete(p.active, ref)
+		ctm = ctm.Translate(vals[0], vals[1])
+		if ref.tag == "symbol" {
+			vp, err := ref.viewport(vals[2], vals[3])
+			if err != nil {
+				return err
+			}
+			return p.children(ref, ctm.Multiply(vp), st.inherit(ref))
+		}
+		return p.draw(ref, ctm, st)
+	}
+	return p.children(s, ctm, st)
+}
+
+// Clip to the clipping path ref applied to s
+func (p *pdfWriter) clipPath(s, ref *SVG, ctm Transform) error {
+	if units, _ := ref.property("clipPathUnits"); units == ObjectBoundingBox {
+		b, err := s.bounds(make(map[*SVG]bool))
+		if err != nil {
+			return err
+		}
+		ctm = ctm.Multiply(boxTransform(b))
+	}
+	if v, ok := ref.a["transform"]; ok {
+		if tr, err := toTransform(v); err == nil {
+			ctm = ctm.Multiply(tr)
+		}
+	}
+
+	// Union of the outlines of the children
+	empty := true
+	for _, c := range ref.mids {
+		if v, ok := c.property("display"); ok && v == "none" {
+			continue
+		}
+		own, err := c.Transform()
+		if err != nil {
+			return err
+		}
+		var d *PathData
+		if c.tag == "text" {
+			d = textPath(c, ancestorStyle(c))
+		} else if d, err = c.shapePath(); err != nil {
+			continue
+		}
+		p.path(d.Normalize(), ctm.Multiply(own))
+		empty = false
+	}
+	if empty {
+		p.out.WriteString("0 0 m h\n")
+	}
+	p.out.WriteString("W n\n")
+	return nil
+}
+
+// Fill and stroke shape s, then draw its markers
+func (p *pdfWriter) shape(s *SVG, ctm Transform, st style) error {
+	d, err := s.shapePath()
+	if err != nil {
+		return err
+	}
+	n := d.Normalize()
+	if st.visible {
+		box, err := s.bounds(make(map[*SVG]bool))
+		if err != nil {
+			return err
+		}
+		fillOp, fillAlpha, fill := p.paint(s, st.fill, false, ctm, box)
+		strokeOp, strokeAlpha, stroke := p.paint(s, st.stroke, true, ctm, box)
+		stroke = stroke && st.strokeWidth > 0
+
+		p.out.WriteString("q\n" + pdfMatrix(ctm) + " cm\n")
+		if alphaF, alphaS := fillAlpha*st.fillOpacity*st.opacity, strokeAlpha*st.strokeOpacity*st.opacity; (fill && alphaF != 1) || (stroke && alphaS != 1) {
+			p.out.WriteString("/" + p.gState(alphaF, alphaS) + " gs\n")
+		}
+		if fill {
+			p.out.WriteString(fillOp + "\n")
+		}
+		if stroke {
+			width := st.strokeWidth
+			if v, _ := s.property("vector-effect"); v == "non-scaling-stroke" {
+				if det := math.Sqrt(math.Abs(ctm.A*ctm.D - ctm.B*ctm.C)); det > 0 {
+					width *= p.scale / det
+				}
+			}
+			cap := map[string]string{"round": "1", "square": "2"}[st.cap]
+			join := map[string]string{"round": "1", "bevel": "2"}[st.join]
+			if cap == "" {
+				cap = "0"
+			}
+			if join == "" {
+				join = "0"
+			}
+			p.out.WriteString(strokeOp + "\n" + pdfNum(width) + " w " + cap + " J " + join + " j " + pdfNum(math.Max(st.miterLimit, 1)) + " M\n")
+		}
+		p.path(n, Identity())
+		op := "n"
+		switch {
+		case fill && stroke:
+			op = "B"
+		case fill:
+			op = "f"
+		case stroke:
+			op = "S"
+		}
+		if fill && st.fillRule == "evenodd" {
+			op += "*"
+		}
+		p.out.WriteString(op + "\nQ\n")
+	}
+
+	placed, err := s.placeMarkers(n, st)
+	if err != nil {
+		return err
+	}
+	for _, pm := range placed {
+		mt := ctm.Multiply(pm.t)
+		p.out.WriteString("q\n")
+		if pm.clip {
+			p.clipRect(mt, 0, 0, pm.width, pm.height)
+		}
+		err := p.children(pm.m, mt.Multiply(pm.content), ancestorStyle(pm.m))
+		p.out.WriteString("Q\n")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Width of text in Courier, in units of the font size
+const courierAdvance = 0.6
+
+// Fill text of s in the standard Courier font
+func (p *pdfWriter) text(s *SVG, ctm Transform, st style) error {
+	vals, err := s.nums("x", "y")
+	if err != nil || !st.visible {
+		return err
+	}
+	op, alpha, ok := p.paint(s, st.fill, false, ctm, bounds{})
+	if !ok {
+		return nil
+	}
+	x := vals[0]
+	width := courierAdvance * st.fontSize * float64(len([]rune(s.data)))
+	switch st.anchor {
+	case "middle":
+		x -= width / 2
+	case "end":
+		x -= width
+	}
+	p.out.WriteString("q\n" + pdfMatrix(ctm) + " cm\n")
+	if a := alpha * st.fillOpacity * st.opacity; a != 1 {
+		p.out.WriteString("/" + p.gState(a, 1) + " gs\n")
+	}
+	// Flip text upright, as user space has y pointing down
+	p.out.WriteString(op + "\nBT\n/F1 " + pdfNum(st.fontSize) + " Tf\n1 0 0 -1 " + pdfNum(x) + " " + pdfNum(vals[1]) + " Tm\n" +
+		pdfString(s.data) + " Tj\nET\nQ\n")
+	return nil
+}
+
+// Write s as a single page pdf. The page has the size in pixels that Render gives at scale 1, with one point per pixel.
+func (s *SVG) WritePDF(w io.Writer) error {
+	vb, ok, err := s.viewBox()
+	if err != nil {
+		return err
+	}
+	width, height, err := s.size(vb, ok)
+	if err != nil {
+		return err
+	}
+	if width <= 0 || height <= 0 {
+		return errors.New("Could not find size of page from viewBox, width or height")
+	}
+
+	// Flip y axis, as pdf has it pointing up
+	ctm := Matrix(1, 0, 0, -1, 0, height)
+	if ok {
+		preserve := "xMidYMid meet"
+		if v, ok := s.a["preserveAspectRatio"]; ok {
+			preserve = fmt.Sprint(v)
+		}
+		ctm = ctm.Multiply(viewBoxTransform(vb, 0, 0, width, height, preserve))
+	}
+	p := &pdfWriter{
+		out:     new(bytes.Buffer),
+		gStates: make(map[[2]float64]string),
+		scale:   1,
+		active:  make(map[*SVG]bool),
+	}
+	if err := p.children(s, ctm, initialStyle.inherit(s)); err != nil {
+		return err
+	}
+
+	resources := "<< /Font << /F1 " + strconv.Itoa(pdfFont) + " 0 R >> /ExtGState << " + strings.Join(p.gStateDefs, " ") +
+		" >> /Pattern << " + strings.Join(p.patterns, " ") + " >> >>"
+	objects := append([]string{
+		pdfCatalog:   fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfPages),
+		pdfPages:     fmt.Sprintf("<< /Type /Pages /Kids [%d 0 R] /Count 1 >>", pdfPage),
+		pdfPage:      fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Contents %d 0 R /Resources %d 0 R >>", pdfPages, pdfNum(width), pdfNum(height), pdfContents, pdfResources),
+		pdfContents:  "<<" + pdfStream(p.out.Bytes()),
+		pdfFont:      "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		pdfResources: resources,
+	}, p.objects...)
+
+	// Objects are numbered from 1, and the xref table gives their offsets
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i := 1; i < len(objects); i++ {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i, objects[i])
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects))
+	for _, off := range offsets[1:] {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects), pdfCatalog, xref)
+	_, err = b.WriteTo(w)
+	return err
+}
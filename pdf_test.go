@@ -0,0 +1,101 @@
+package smartSVG
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Write document with root attributes atts as pdf
+func writePDF(t *testing.T, atts string) ([]byte, error) {
+	s, err := Parse(strings.NewReader(`<svg xmlns="http://www.w3.org/2000/svg" ` + atts + `><rect width="10" height="10" fill="red" /></svg>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	err = s.WritePDF(&b)
+	return b.Bytes(), err
+}
+
+func TestPDFPageSize(t *testing.T) {
+	tests := []struct {
+		atts, want string
+	}{
+		{`viewBox="0 0 200 100"`, "200 100"},
+		{`width="300" height="150" viewBox="0 0 200 100"`, "300 150"},
+		{`width="100%" height="50%" viewBox="0 0 200 100"`, "200 50"},
+		{`width="1in" height="2in"`, "96 192"},
+		{`width="10cm" height="5cm" viewBox="0 0 10 5"`, "377.9528 188.9764"},
+		{`width="12pt" height="3em"`, "16 48"},
+	}
+	mediaBox := regexp.MustCompile(`/MediaBox \[0 0 (\S+ \S+)\]`)
+	for _, test := range tests {
+		data, err := writePDF(t, test.atts)
+		if err != nil {
+			t.Errorf("WritePDF of <svg %s> gave error %v", test.atts, err)
+			continue
+		}
+		if m := mediaBox.FindSubmatch(data); m == nil || string(m[1]) != test.want {
+			t.Errorf("Page of <svg %s> has MediaBox %q, want %q", test.atts, m, test.want)
+		}
+	}
+	for _, atts := range []string{``, `width="100%" height="100%"`, `width="0" height="10"`} {
+		if _, err := writePDF(t, atts); err == nil {
+			t.Errorf("WritePDF of <svg %s> gave no error", atts)
+		}
+	}
+}
+
+func TestPDFStructure(t *testing.T) {
+	data, err := writePDF(t, `viewBox="0 0 20 10"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatalf("Output is not framed as pdf:\n%s", data)
+	}
+
+	// Every entry of the cross-reference table points at its object
+	m := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(data)
+	if m == nil {
+		t.Fatal("Found no startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(data[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %v does not point at the xref table", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[xref:], -1)
+	if len(entries) == 0 {
+		t.Fatal("Cross-reference table has no objects")
+	}
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		if !bytes.HasPrefix(data[off:], []byte(fmt.Sprint(i+1, " 0 obj"))) {
+			t.Errorf("Offset %v of object %v points at %q", off, i+1, data[off:off+10])
+		}
+	}
+
+	// The red rect is filled in the flipped coordinate system of the page
+	var content string
+	for _, s := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllSubmatch(data, -1) {
+		z, err := zlib.NewReader(bytes.NewReader(s[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content += string(b)
+	}
+	for _, want := range []string{"1 0 0 -1 0 10 cm", "1 0 0 rg"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Content has no %q:\n%s", want, content)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package smartSVG
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Options of Pie. The zero value gives a pie starting at the top, going clockwise.
+type PieOptions struct {
+	InnerRadius      float64   // Radius of the hole of donuts, 0 for pies
+	StartAngle       float64   // Angle in degrees where the first slice starts, clockwise from the top
+	CounterClockwise bool      // Place slices counter-clockwise instead of clockwise
+	Explode          []float64 // Distance in pixels each slice is moved out from the centre, if set
+	Percentages      bool      // Label slices with their percentage outside the pie, connected by leader lines
+}
+
+// Point at radius r and angle in degrees clockwise from the top
+func polar(r, angle float64) (float64, float64) {
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	return r * sin, -r * cos
+}
+
+// Draw pie chart centred at x, y with outer radius r, with one slice per value. Labels name the slices.
+// The chart works with Legend and SeriesFill like Diagram.
+func (s *SVG) Pie(x, y, r float64, values []float64, labels []string, opts PieOptions) (*SVG, error) {
+	var sum float64
+	for _, v := range values {
+		if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, errors.New("Value " + fmt.Sprint(v) + " of slice is not a non-negative number")
+		}
+		sum += v
+	}
+	switch {
+	case sum == 0:
+		return nil, errors.New("Got no values to divide pie by")
+	case len(labels) != len(values):
+		return nil, errors.New("Got " + fmt.Sprint(len(labels)) + " labels for " + fmt.Sprint(len(values)) + " slices")
+	case len(opts.Explode) != 0 && len(opts.Explode) != len(values):
+		return nil, errors.New("Got " + fmt.Sprint(len(opts.Explode)) + " explode distances for " + fmt.Sprint(len(values)) + " slices")
+	case r <= 0 || opts.InnerRadius < 0 || opts.InnerRadius >= r:
+		return nil, errors.New("Inner radius must be between 0 and the outer radius")
+	}
+	dir := 1.0
+	if opts.CounterClockwise {
+		dir = -1
+	}
+
+	top := s.GID("diagram"+s.newDiagramSuffix(), SumAtts(Translate(x, y), Att{"class": "pie"}))
+	top.startColours()
+	slices := top.G(Att{"stroke": "white", "stroke-width": "1"})
+	var texts *SVG
+	if opts.Percentages {
+		texts = top.G(Att{"class": "labels", "fill": "black"})
+	}
+
+	angle := opts.StartAngle
+	for i, v := range values {
+		sweep := 360 * v / sum
+		start, end, mid := angle, angle+dir*sweep, angle+dir*sweep/2
+		angle = end
+
+		var explode float64
+		if len(opts.Explode) != 0 {
+			explode = opts.Explode[i]
+		}
+		slice := top.addSeries(slices.Path(sliceData(r, opts.InnerRadius, start, end, sweep), Att{"class": "series", "fill": top.NextColour().String()}))
+		if explode != 0 {
+			slice.AddAtt(false, Translate(polar(explode, mid)))
+		}
+		slice.Title(labels[i])
+
+		if texts == nil || v == 0 {
+			continue
+		}
+		// Leader line goes out from the slice, then to the side of the label
+		x1, y1 := polar(r+explode, mid)
+		x2, y2 := polar(r+explode+12, mid)
+		side, anchor := 8.0, "start"
+		if x2 < 0 {
+			side, anchor = -8, "end"
+		}
+		texts.Path(NewPathData().MoveTo(x1, y1).LineTo(x2, y2).HLine(x2+side), Att{"stroke": "grey", "fill": "none"})
+		texts.TextF(x2+1.5*side, y2+chartTextHeight/3, Decimals(1).Format(100*v/sum)+"%", Att{"text-anchor": anchor})
+	}
+	return top, nil
+}
+
+// Path of slice between the angles start and end, spanning sweep degrees, from inner radius ri to r
+func sliceData(r, ri, start, end, sweep float64) *PathData {
+	p := NewPathData()
+	clockwise := end > start
+
+	// Arcs can not draw whole circles, so they are drawn in halves
+	arc := func(r, from, to float64, clockwise bool) {
+		if sweep >= 360 {
+			x, y := polar(r, (from+to)/2)
+			p.ArcTo(r, r, 0, false, clockwise, x, y)
+		}
+		x, y := polar(r, to)
+		p.ArcTo(r, r, 0, sweep > 180 && sweep < 360, clockwise, x, y)
+	}
+	switch {
+	case sweep >= 360 && ri > 0:
+		// Ring with hole as inner circle in the opposite direction
+		p.MoveTo(polar(r, start))
+		arc(r, start, end, clockwise)
+		p.Close()
+		p.MoveTo(polar(ri, end))
+		arc(ri, end, start, !clockwise)
+		p.Close()
+	case sweep >= 360:
+		p.MoveTo(polar(r, start))
+		arc(r, start, end, clockwise)
+		p.Close()
+	case ri > 0:
+		p.MoveTo(polar(r, start))
+		arc(r, start, end, clockwise)
+		p.LineTo(polar(ri, end))
+		arc(ri, end, start, !clockwise)
+		p.Close()
+	default:
+		p.MoveTo(0, 0)
+		p.LineTo(polar(r, start))
+		arc(r, start, end, clockwise)
+		p.Close()
+	}
+	return p
+}
+
+// Legend of pie beside it, listing the slices. Slices are described by their labels if desc is empty.
+func (s *SVG) pieLegend(desc []string) (*SVG, error) {
+	slices := s.series()
+	if len(desc) == 0 {
+		for _, slice := range slices {
+			for _, c := range slice.mids {
+				if c.tag == "title" {
+					desc = append(desc, c.data)
+				}
+			}
+		}
+	}
+	if len(desc) != len(slices) {
+		return nil, errors.New("Amount of slices found is not the same as the amount of descriptors given. #Slices: " + fmt.Sprint(len(slices)) + " #Desc: " + fmt.Sprint(len(desc)))
+	}
+	box, err := s.BBox()
+	if err != nil {
+		return nil, err
+	}
+
+	rowHeight := 1.5 * chartTextHeight
+	legend := s.GID("legend"+s.idSuffix(), Translate(box.X+box.Width+chartTextHeight, box.Y))
+	for i, slice := range slices {
+		colour, err := s.seriesPaint(slice)
+		if err != nil {
+			colour = s.currentPalette().At(i).String()
+		}
+		y := rowHeight * float64(i)
+		legend.RectF(0, y, chartTextHeight, chartTextHeight, Att{"fill": colour})
+		legend.TextF(1.5*chartTextHeight, y+chartTextHeight-1, desc[i], Att{"fill": "black"})
+	}
+	return legend, nil
+}
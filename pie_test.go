@@ -0,0 +1,96 @@
+package smartSVG
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPieErrors(t *testing.T) {
+	tests := []struct {
+		values []float64
+		labels []string
+		opts   PieOptions
+	}{
+		{[]float64{1, -1}, []string{"a", "b"}, PieOptions{}},
+		{[]float64{0, 0}, []string{"a", "b"}, PieOptions{}},
+		{[]float64{1, 2}, []string{"a"}, PieOptions{}},
+		{[]float64{1, 2}, []string{"a", "b"}, PieOptions{Explode: []float64{1}}},
+		{[]float64{1, 2}, []string{"a", "b"}, PieOptions{InnerRadius: 10}},
+	}
+	for _, test := range tests {
+		if _, err := New(100, 100).Pie(50, 50, 10, test.values, test.labels, test.opts); err == nil {
+			t.Errorf("Pie of %v with labels %q and options %+v gave no error", test.values, test.labels, test.opts)
+		}
+	}
+}
+
+func TestPieSlices(t *testing.T) {
+	tests := []struct {
+		values []float64
+		opts   PieOptions
+		want   []Box // Bounding box of every slice, with its explode offset
+	}{
+		{[]float64{1, 1, 2}, PieOptions{}, []Box{{0, -10, 10, 10}, {0, 0, 10, 10}, {-10, -10, 10, 20}}},
+		{[]float64{1, 3}, PieOptions{CounterClockwise: true}, []Box{{-10, -10, 10, 10}, {-10, -10, 20, 20}}},
+		{[]float64{1, 3}, PieOptions{StartAngle: 90, Explode: []float64{0, 2}}, []Box{{0, 0, 10, 10}, {-10 - 2*0.7071067811865476, -10 - 2*0.7071067811865476, 20, 20}}},
+		{[]float64{1}, PieOptions{InnerRadius: 5}, []Box{{-10, -10, 20, 20}}},
+	}
+	for _, test := range tests {
+		labels := make([]string, len(test.values))
+		p, err := New(100, 100).Pie(0, 0, 10, test.values, labels, test.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, slice := range p.series() {
+			b, err := slice.bounds(map[*SVG]bool{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			tr, err := slice.Transform()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var moved bounds
+			moved.union(b, tr)
+			if got := moved.box(); !closeBoxes(got, test.want[i]) {
+				t.Errorf("Slice %d of %v with options %+v has box %v, want %v", i, test.values, test.opts, got, test.want[i])
+			}
+		}
+	}
+}
+
+func TestPieLegend(t *testing.T) {
+	s := New(400, 200)
+	first, err := s.Pie(60, 60, 50, []float64{5, 3, 0}, []string{"ok", "warn", "none"}, PieOptions{Percentages: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := first.Legend(); err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.Pie(260, 60, 50, []float64{1, 1}, []string{"a", "b"}, PieOptions{InnerRadius: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := second.SeriesFill("red", "blue"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := second.Legend("x"); err == nil {
+		t.Error("Legend with too few descriptions gave no error")
+	}
+	if _, err := second.Legend("x", "y"); err != nil {
+		t.Fatal(err)
+	}
+	got := s.String()
+	for _, want := range []string{`>62.5%</text>`, `>37.5%</text>`, `>ok</text>`, `>none</text>`, `>y</text>`, `fill="blue"`, `id="legend-2"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Pies have no %s:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `>0.0%<`) {
+		t.Errorf("Empty slice is labelled:\n%s", got)
+	}
+	if issues := s.Validate(); len(issues) != 0 {
+		t.Errorf("Document with two pies has issues %v", issues)
+	}
+}
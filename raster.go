@@ -0,0 +1,324 @@
+package smartSVG
+
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+type point struct {
+	x, y float64
+}
+
+// Sub scanlines sampled per pixel row
+const subScanlines = 5
+
+// Coverage of a shape on pixels of rect, from 0 to 1
+type coverage struct {
+	rect image.Rectangle
+	a    []float32
+}
+
+func (c *coverage) at(x, y int) float32 {
+	return c.a[(y-c.rect.Min.Y)*c.rect.Dx()+x-c.rect.Min.X]
+}
+
+type edge struct {
+	x0, y0, x1, y1 float64
+	dir            int
+}
+
+// Compute coverage of polygons within clip, by fill rule nonzero or evenodd.
+// Polygons are closed implicitly.
+func rasterize(polys [][]point, evenOdd bool, clip image.Rectangle) *coverage {
+	var edges []edge
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range polys {
+		for i := range p {
+			a, b := p[i], p[(i+1)%len(p)]
+			minX, maxX = math.Min(minX, a.x), math.Max(maxX, a.x)
+			minY, maxY = math.Min(minY, a.y), math.Max(maxY, a.y)
+			switch {
+			case a.y < b.y:
+				edges = append(edges, edge{a.x, a.y, b.x, b.y, 1})
+			case a.y > b.y:
+				edges = append(edges, edge{b.x, b.y, a.x, a.y, -1})
+			}
+		}
+	}
+	rect := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX))+1, int(math.Ceil(maxY))+1)
+	if len(edges) == 0 {
+		rect = image.Rectangle{}
+	}
+	rect = rect.Intersect(clip)
+	c := &coverage{rect: rect, a: make([]float32, rect.Dx()*rect.Dy())}
+	if rect.Empty() {
+		return c
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].y0 < edges[j].y0 })
+
+	type crossing struct {
+		x   float64
+		dir int
+	}
+	var (
+		active    []edge
+		crossings []crossing
+		next      int
+	)
+	width := rect.Dx()
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		row := c.a[(y-rect.Min.Y)*width : (y-rect.Min.Y+1)*width]
+		for sub := 0; sub < subScanlines; sub++ {
+			sy := float64(y) + (float64(sub)+0.5)/subScanlines
+
+			// Update active edges
+			for next < len(edges) && edges[next].y0 <= sy {
+				active = append(active, edges[next])
+				next++
+			}
+			kept := active[:0]
+			crossings = crossings[:0]
+			for _, e := range active {
+				if e.y1 <= sy {
+					continue
+				}
+				kept = append(kept, e)
+				if e.y0 <= sy {
+					x := e.x0 + (sy-e.y0)/(e.y1-e.y0)*(e.x1-e.x0)
+					crossings = append(crossings, crossing{x, e.dir})
+				}
+			}
+			active = kept
+			sort.Slice(crossings, func(i, j int) bool { return crossings[i].x < crossings[j].x })
+
+			// Add spans where the fill rule is satisfied
+			winding := 0
+			for i := 0; i+1 < len(crossings); i++ {
+				winding += crossings[i].dir
+				inside := winding != 0
+				if evenOdd {
+					inside = winding%2 != 0
+				}
+				if inside {
+					addSpan(row, crossings[i].x-float64(rect.Min.X), crossings[i+1].x-float64(rect.Min.X), 1.0/subScanlines)
+				}
+			}
+		}
+	}
+	return c
+}
+
+// Add weight times the horizontal coverage of span x0 to x1 to row
+func addSpan(row []float32, x0, x1, weight float64) {
+	x0 = math.Max(x0, 0)
+	x1 = math.Min(x1, float64(len(row)))
+	if x1 <= x0 {
+		return
+	}
+	i0, i1 := int(x0), int(x1)
+	if i0 == i1 {
+		row[i0] += float32((x1 - x0) * weight)
+		return
+	}
+	row[i0] += float32((float64(i0+1) - x0) * weight)
+	for i := i0 + 1; i < i1; i++ {
+		row[i] += float32(weight)
+	}
+	if i1 < len(row) {
+		row[i1] += float32((x1 - float64(i1)) * weight)
+	}
+}
+
+// Flatten normalized path data into polygons in device space.
+// closed tells whether each subpath was closed by Z.
+func flatten(d *PathData, t Transform) (paths [][]point, closed []bool) {
+	var (
+		cur   []point
+		start point
+	)
+	// Subpaths of a lone moveto draw nothing
+	finish := func(c bool) {
+		if len(cur) > 1 {
+			paths = append(paths, cur)
+			closed = append(closed, c)
+		}
+		cur = nil
+	}
+	// Current point in device space, where a drawing command continues from
+	last := func() point {
+		if len(cur) == 0 {
+			cur = []point{start}
+		}
+		return cur[len(cur)-1]
+	}
+	for _, c := range d.Commands {
+		switch c.Command {
+		case 'M':
+			finish(false)
+			x, y := t.Apply(c.Args[0], c.Args[1])
+			start = point{x, y}
+			cur = []point{start}
+		case 'L':
+			last()
+			x, y := t.Apply(c.Args[0], c.Args[1])
+			cur = append(cur, point{x, y})
+		case 'C':
+			p0 := last()
+			p1x, p1y := t.Apply(c.Args[0], c.Args[1])
+			p2x, p2y := t.Apply(c.Args[2], c.Args[3])
+			p3x, p3y := t.Apply(c.Args[4], c.Args[5])
+			length := math.Hypot(p1x-p0.x, p1y-p0.y) + math.Hypot(p2x-p1x, p2y-p1y) + math.Hypot(p3x-p2x, p3y-p2y)
+			n := int(math.Ceil(math.Sqrt(2 * length)))
+			if n < 1 {
+				n = 1
+			}
+			for i := 1; i <= n; i++ {
+				s := float64(i) / float64(n)
+				m := 1 - s
+				cur = append(cur, point{
+					m*m*m*p0.x + 3*m*m*s*p1x + 3*m*s*s*p2x + s*s*s*p3x,
+					m*m*m*p0.y + 3*m*m*s*p1y + 3*m*s*s*p2y + s*s*s*p3y,
+				})
+			}
+		case 'Z':
+			last()
+			finish(true)
+		}
+	}
+	finish(false)
+	return paths, closed
+}
+
+// Signed area of polygon, positive when counter-clockwise on screen
+func area(p []point) float64 {
+	a := 0.0
+	for i := range p {
+		q := p[(i+1)%len(p)]
+		a += p[i].x*q.y - q.x*p[i].y
+	}
+	return a / 2
+}
+
+// Give polygon negative orientation, so that pieces of a stroke add up under the nonzero rule
+func orient(p []point) []point {
+	if area(p) > 0 {
+		for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+			p[i], p[j] = p[j], p[i]
+		}
+	}
+	return p
+}
+
+func circle(c point, r float64) []point {
+	n := int(math.Ceil(math.Sqrt(r) * 6))
+	if n < 8 {
+		n = 8
+	}
+	p := make([]point, n)
+	for i := range p {
+		sin, cos := math.Sincos(2 * math.Pi * float64(i) / float64(n))
+		p[i] = point{c.x + r*cos, c.y + r*sin}
+	}
+	return orient(p)
+}
+
+// Stroke style in device space
+type strokeStyle struct {
+	width      float64
+	cap, join  string
+	miterLimit float64
+}
+
+// Outline of stroked polylines, as polygons to be filled with the nonzero rule
+func strokeOutline(paths [][]point, closed []bool, st strokeStyle) [][]point {
+	hw := st.width / 2
+	var out [][]point
+	for k, path := range paths {
+		// Remove repeated points
+		p := []point{path[0]}
+		for _, q := range path[1:] {
+			if q != p[len(p)-1] {
+				p = append(p, q)
+			}
+		}
+		if closed[k] && len(p) > 1 && p[0] == p[len(p)-1] {
+			p = p[:len(p)-1]
+		}
+
+		if len(p) == 1 {
+			switch st.cap {
+			case "round":
+				out = append(out, circle(p[0], hw))
+			case "square":
+				c := p[0]
+				out = append(out, orient([]point{{c.x - hw, c.y - hw}, {c.x + hw, c.y - hw}, {c.x + hw, c.y + hw}, {c.x - hw, c.y + hw}}))
+			}
+			continue
+		}
+
+		segs := len(p) - 1
+		if closed[k] {
+			segs = len(p)
+		}
+		normal := func(a, b point) point {
+			l := math.Hypot(b.x-a.x, b.y-a.y)
+			return point{-(b.y - a.y) / l * hw, (b.x - a.x) / l * hw}
+		}
+		for i := 0; i < segs; i++ {
+			a, b := p[i], p[(i+1)%len(p)]
+			n := normal(a, b)
+			out = append(out, orient([]point{{a.x + n.x, a.y + n.y}, {b.x + n.x, b.y + n.y}, {b.x - n.x, b.y - n.y}, {a.x - n.x, a.y - n.y}}))
+		}
+
+		// Joins at interior vertices, and at the start of closed paths
+		for i := 0; i < len(p); i++ {
+			if !closed[k] && (i == 0 || i == len(p)-1) {
+				continue
+			}
+			prev, v, next := p[(i+len(p)-1)%len(p)], p[i], p[(i+1)%len(p)]
+			n1, n2 := normal(prev, v), normal(v, next)
+
+			// Join on the outer side of the turn
+			if cross := (v.x-prev.x)*(next.y-v.y) - (v.y-prev.y)*(next.x-v.x); cross > 0 {
+				n1, n2 = point{-n1.x, -n1.y}, point{-n2.x, -n2.y}
+			}
+			a, b := point{v.x + n1.x, v.y + n1.y}, point{v.x + n2.x, v.y + n2.y}
+			switch st.join {
+			case "round":
+				out = append(out, circle(v, hw))
+			case "bevel":
+				out = append(out, orient([]point{v, a, b}))
+			default:
+				m := point{n1.x + n2.x, n1.y + n2.y}
+				ml := math.Hypot(m.x, m.y)
+				cos := ml / (2 * hw) // Cosine of half the angle between the normals
+				if ml == 0 || 1/cos > st.miterLimit {
+					out = append(out, orient([]point{v, a, b}))
+					break
+				}
+				l := hw / cos
+				tip := point{v.x + m.x/ml*l, v.y + m.y/ml*l}
+				out = append(out, orient([]point{v, a, tip, b}))
+			}
+		}
+
+		// Caps at the ends of open paths
+		if !closed[k] {
+			for _, end := range [][2]point{{p[1], p[0]}, {p[len(p)-2], p[len(p)-1]}} {
+				from, e := end[0], end[1]
+				switch st.cap {
+				case "round":
+					out = append(out, circle(e, hw))
+				case "square":
+					l := math.Hypot(e.x-from.x, e.y-from.y)
+					d := point{(e.x - from.x) / l * hw, (e.y - from.y) / l * hw}
+					n := normal(from, e)
+					out = append(out, orient([]point{{e.x + n.x, e.y + n.y}, {e.x + n.x + d.x, e.y + n.y + d.y}, {e.x - n.x + d.x, e.y - n.y + d.y}, {e.x - n.x, e.y - n.y}}))
+				}
+			}
+		}
+	}
+	return out
+}
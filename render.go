@@ -0,0 +1,961 @@
+package smartSVG
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Inherited presentation properties while rendering
+type style struct {
+	fill, stroke               string
+	fillOpacity, strokeOpacity float64
+	opacity                    float64 // Product of group opacities, as groups are not composited separately
+	strokeWidth, miterLimit    float64
+	fillRule, cap, join        string
+	markerStart, markerMid     string
+	markerEnd                  string
+	fontSize                   float64
+	anchor                     string
+	visible                    bool
+}
+
+var initialStyle = style{
+	fill: "black", stroke: "none",
+	fillOpacity: 1, strokeOpacity: 1, opacity: 1,
+	strokeWidth: 1, miterLimit: 4,
+	fillRule: "nonzero", cap: "butt", join: "miter",
+	markerStart: "none", markerMid: "none", markerEnd: "none",
+	fontSize: defaultFontSize, anchor: "start", visible: true,
+}
+
+// Read presentation property of s, from the style attribute or the presentation attribute
+func (s *SVG) property(key string) (string, bool) {
+	if v, ok := s.a["style"]; ok {
+		for _, decl := range strings.Split(fmt.Sprint(v), ";") {
+			if kv := strings.SplitN(decl, ":", 2); len(kv) == 2 && strings.TrimSpace(kv[0]) == key {
+				return strings.TrimSpace(kv[1]), true
+			}
+		}
+	}
+	if v, ok := s.a[key]; ok {
+		return strings.TrimSpace(fmt.Sprint(v)), true
+	}
+	return "", false
+}
+
+// Style of s given the style of its parent
+func (st style) inherit(s *SVG) style {
+	str := func(key string, dst *string) {
+		if v, ok := s.property(key); ok && v != "inherit" {
+			*dst = v
+		}
+	}
+	num := func(key string, dst *float64) {
+		if v, ok := s.property(key); ok {
+			if f, err := strconv.ParseFloat(strings.TrimSuffix(v, "px"), 64); err == nil {
+				*dst = f
+			}
+		}
+	}
+	str("fill", &st.fill)
+	str("stroke", &st.stroke)
+	num("fill-opacity", &st.fillOpacity)
+	num("stroke-opacity", &st.strokeOpacity)
+	num("stroke-width", &st.strokeWidth)
+	num("stroke-miterlimit", &st.miterLimit)
+	str("fill-rule", &st.fillRule)
+	str("stroke-linecap", &st.cap)
+	str("stroke-linejoin", &st.join)
+	str("marker", &st.markerStart)
+	str("marker", &st.markerMid)
+	str("marker", &st.markerEnd)
+	str("marker-start", &st.markerStart)
+	str("marker-mid", &st.markerMid)
+	str("marker-end", &st.markerEnd)
+	num("font-size", &st.fontSize)
+	str("text-anchor", &st.anchor)
+	if v, ok := s.property("visibility"); ok {
+		st.visible = v == "visible"
+	}
+	opacity := 1.0
+	num("opacity", &opacity)
+	st.opacity *= opacity
+	return st
+}
+
+// Parse colour into premultiplied components between 0 and 1
+func parseColour(str string) (r, g, b, a float64, err error) {
+	c, err := ParseColour(str)
+	return c.R * c.A, c.G * c.A, c.B * c.A, c.A, err
+}
+
+// Premultiplied colour at device point x, y
+type paintFunc func(x, y float64) (r, g, b, a float64)
+
+type renderer struct {
+	img    *image.RGBA
+	scale  float64       // Device pixels per root user unit, used for strokes which do not scale
+	active map[*SVG]bool // Groups referenced by use or paint being drawn, to break cycles
+}
+
+// Composite paint onto image where covered
+func (r *renderer) composite(c *coverage, paint paintFunc, opacity float64, clip []float32) {
+	width := r.img.Rect.Dx()
+	for y := c.rect.Min.Y; y < c.rect.Max.Y; y++ {
+		for x := c.rect.Min.X; x < c.rect.Max.X; x++ {
+			k := math.Min(float64(c.at(x, y)), 1) * opacity
+			if clip != nil {
+				k *= float64(clip[y*width+x])
+			}
+			if k <= 0 {
+				continue
+			}
+			pr, pg, pb, pa := paint(float64(x)+0.5, float64(y)+0.5)
+			i := r.img.PixOffset(x, y)
+			pix := r.img.Pix[i : i+4]
+			inv := 1 - pa*k
+			pix[0] = uint8(math.Min(255, pr*k*255+float64(pix[0])*inv+0.5))
+			pix[1] = uint8(math.Min(255, pg*k*255+float64(pix[1])*inv+0.5))
+			pix[2] = uint8(math.Min(255, pb*k*255+float64(pix[2])*inv+0.5))
+			pix[3] = uint8(math.Min(255, pa*k*255+float64(pix[3])*inv+0.5))
+		}
+	}
+}
+
+// Clip mask of coverage over the whole image, intersected with clip
+func (r *renderer) mask(c *coverage, clip []float32) []float32 {
+	width := r.img.Rect.Dx()
+	m := make([]float32, width*r.img.Rect.Dy())
+	for y := c.rect.Min.Y; y < c.rect.Max.Y; y++ {
+		for x := c.rect.Min.X; x < c.rect.Max.X; x++ {
+			v := float32(math.Min(float64(c.at(x, y)), 1))
+			if clip != nil {
+				v *= clip[y*width+x]
+			}
+			m[y*width+x] = v
+		}
+	}
+	return m
+}
+
+// Polygon of rectangle x, y, width, height mapped through t
+func rectPolygon(t Transform, x, y, width, height float64) [][]point {
+	var p []point
+	for _, c := range [][2]float64{{x, y}, {x + width, y}, {x + width, y + height}, {x, y + height}} {
+		px, py := t.Apply(c[0], c[1])
+		p = append(p, point{px, py})
+	}
+	return [][]point{p}
+}
+
+// Find element referenced by url(#id) paint or property value
+func (s *SVG) reference(value string) *SVG {
+	if !strings.HasPrefix(value, "url(") {
+		return nil
+	}
+	end := strings.Index(value, ")")
+	if end == -1 {
+		return nil
+	}
+	id := strings.Trim(strings.TrimSpace(value[4:end]), `"'`)
+	return s.root().FindID(strings.TrimPrefix(id, "#"))
+}
+
+// Resolve paint of element s. Returns nil for no paint. box is the bounding box of s, used for objectBoundingBox units.
+func (r *renderer) paint(s *SVG, value string, opacity float64, t Transform, box bounds) paintFunc {
+	if value == "none" || value == "" {
+		return nil
+	}
+	if strings.HasPrefix(value, "url(") {
+		if ref := s.reference(value); ref != nil {
+			switch ref.tag {
+			case "linearGradient", "radialGradient":
+				if p := r.gradient(ref, opacity, t, box); p != nil {
+					return p
+				}
+			case "pattern":
+				if p := r.pattern(ref, opacity, t, box); p != nil {
+					return p
+				}
+			}
+		}
+		// Fallback colour after the reference
+		value = strings.TrimSpace(value[strings.Index(value, ")")+1:])
+	}
+	if value == "currentColor" {
+		value = "black"
+		if v, ok := s.inherited("color"); ok {
+			value = fmt.Sprint(v)
+		}
+	}
+	cr, cg, cb, ca, err := parseColour(value)
+	if err != nil {
+		return nil
+	}
+	cr, cg, cb, ca = cr*opacity, cg*opacity, cb*opacity, ca*opacity
+	return func(x, y float64) (float64, float64, float64, float64) { return cr, cg, cb, ca }
+}
+
+// Transform from objectBoundingBox units to user space
+func boxTransform(box bounds) Transform {
+	return Matrix(box.maxX-box.minX, 0, 0, box.maxY-box.minY, box.minX, box.minY)
+}
+
+// Read gradient stop offset, given as number or percentage
+func stopOffset(v string) float64 {
+	div := 1.0
+	if strings.HasSuffix(v, "%") {
+		v, div = v[:len(v)-1], 100
+	}
+	f, _ := strconv.ParseFloat(v, 64)
+	return math.Max(0, math.Min(1, f/div))
+}
+
+// Colour stop of a gradient, premultiplied
+type gradientStop struct {
+	offset     float64
+	r, g, b, a float64
+}
+
+// Stops of gradient g, which may be inherited through href
+func (g *SVG) gradientStops() []gradientStop {
+	src := g
+	for i := 0; i < 10 && len(src.FindGroups("stop")) == 0; i++ {
+		if src = src.href(); src == nil {
+			return nil
+		}
+	}
+	var stops []gradientStop
+	for _, s := range src.FindGroups("stop") {
+		off, _ := s.property("offset")
+		colour, ok := s.property("stop-color")
+		if !ok {
+			colour = "black"
+		}
+		sr, sg, sb, sa, err := parseColour(colour)
+		if err != nil {
+			continue
+		}
+		op := 1.0
+		if v, ok := s.property("stop-opacity"); ok {
+			op, _ = strconv.ParseFloat(v, 64)
+		}
+		o := stopOffset(off)
+		if len(stops) > 0 && o < stops[len(stops)-1].offset {
+			o = stops[len(stops)-1].offset
+		}
+		stops = append(stops, gradientStop{o, sr * op, sg * op, sb * op, sa * op})
+	}
+	return stops
+}
+
+// Transform from the space of gradient g to user space, given the bounding box of the painted element
+func (g *SVG) gradientSpace(box bounds) (Transform, bool) {
+	t := Identity()
+	if units, _ := g.property("gradientUnits"); units != UserSpaceOnUse {
+		if !box.ok || box.maxX == box.minX || box.maxY == box.minY {
+			return t, false
+		}
+		t = boxTransform(box)
+	}
+	if v, ok := g.a["gradientTransform"]; ok {
+		if tr, err := toTransform(v); err == nil {
+			t = t.Multiply(tr)
+		}
+	}
+	return t, true
+}
+
+// Read gradient coordinate. Missing coordinates give def, in units of the bounding box.
+func (g *SVG) gradientCoord(key string, def float64) float64 {
+	v, ok := g.property(key)
+	if !ok {
+		return def
+	}
+	if strings.HasSuffix(v, "%") {
+		f, _ := strconv.ParseFloat(v[:len(v)-1], 64)
+		return f / 100
+	}
+	f, _ := strconv.ParseFloat(v, 64)
+	return f
+}
+
+// Paint of linear or radial gradient
+func (r *renderer) gradient(g *SVG, opacity float64, t Transform, box bounds) paintFunc {
+	stops := g.gradientStops()
+	if len(stops) == 0 {
+		return nil
+	}
+	for i := range stops {
+		stops[i].r, stops[i].g, stops[i].b, stops[i].a = stops[i].r*opacity, stops[i].g*opacity, stops[i].b*opacity, stops[i].a*opacity
+	}
+
+	// Transform from device space to gradient space
+	gt, ok := g.gradientSpace(box)
+	if !ok {
+		return nil
+	}
+	inv, err := t.Multiply(gt).Invert()
+	if err != nil {
+		return nil
+	}
+	coord := g.gradientCoord
+	spread, _ := g.property("spreadMethod")
+
+	var param func(x, y float64) float64
+	if g.tag == "linearGradient" {
+		x1, y1, x2, y2 := coord("x1", 0), coord("y1", 0), coord("x2", 1), coord("y2", 0)
+		dx, dy := x2-x1, y2-y1
+		l := dx*dx + dy*dy
+		param = func(x, y float64) float64 {
+			if l == 0 {
+				return 1
+			}
+			return ((x-x1)*dx + (y-y1)*dy) / l
+		}
+	} else {
+		cx, cy, rad := coord("cx", 0.5), coord("cy", 0.5), coord("r", 0.5)
+		fx, fy := coord("fx", cx), coord("fy", cy)
+		param = func(x, y float64) float64 {
+			// Largest t where x, y lies on the circle of radius t*r centred between focus and centre
+			dx, dy := x-fx, y-fy
+			ex, ey := cx-fx, cy-fy
+			a := ex*ex + ey*ey - rad*rad
+			b := dx*ex + dy*ey
+			c := dx*dx + dy*dy
+			if math.Abs(a) < 1e-12 {
+				if b == 0 {
+					return 0
+				}
+				return c / (2 * b)
+			}
+			disc := b*b - a*c
+			if disc < 0 {
+				return 0
+			}
+			return math.Max((b+math.Sqrt(disc))/a, (b-math.Sqrt(disc))/a)
+		}
+	}
+
+	return func(x, y float64) (float64, float64, float64, float64) {
+		tx := param(inv.Apply(x, y))
+		switch spread {
+		case SpreadRepeat:
+			tx -= math.Floor(tx)
+		case SpreadReflect:
+			tx = math.Abs(tx - 2*math.Floor(tx/2))
+			if tx > 1 {
+				tx = 2 - tx
+			}
+		}
+		if tx <= stops[0].offset {
+			s := stops[0]
+			return s.r, s.g, s.b, s.a
+		}
+		for i := 1; i < len(stops); i++ {
+			if tx <= stops[i].offset {
+				s0, s1 := stops[i-1], stops[i]
+				f := 0.0
+				if s1.offset > s0.offset {
+					f = (tx - s0.offset) / (s1.offset - s0.offset)
+				}
+				return s0.r + f*(s1.r-s0.r), s0.g + f*(s1.g-s0.g), s0.b + f*(s1.b-s0.b), s0.a + f*(s1.a-s0.a)
+			}
+		}
+		s := stops[len(stops)-1]
+		return s.r, s.g, s.b, s.a
+	}
+}
+
+// Paint of pattern, rendering one tile and repeating it
+func (r *renderer) pattern(p *SVG, opacity float64, t Transform, box bounds) paintFunc {
+	if r.active[p] {
+		return nil
+	}
+	r.active[p] = true
+	defer delete(r.active, p)
+	vals, err := p.nums("x", "y", "width", "height")
+	if err != nil || vals[2] <= 0 || vals[3] <= 0 {
+		return nil
+	}
+	pt := t
+	if units, _ := p.property("patternUnits"); units != UserSpaceOnUse {
+		if !box.ok {
+			return nil
+		}
+		bt := boxTransform(box)
+		vals[0], vals[1] = bt.Apply(vals[0], vals[1])
+		vals[2], vals[3] = vals[2]*bt.A, vals[3]*bt.D
+	}
+	if v, ok := p.a["patternTransform"]; ok {
+		if tr, err := toTransform(v); err == nil {
+			pt = pt.Multiply(tr)
+		}
+	}
+	inv, err := pt.Invert()
+	if err != nil {
+		return nil
+	}
+
+	// Render tile at device resolution
+	res := math.Sqrt(math.Abs(pt.A*pt.D - pt.B*pt.C))
+	w, h := int(math.Ceil(vals[2]*res)), int(math.Ceil(vals[3]*res))
+	if w <= 0 || h <= 0 || w*h > 1<<22 {
+		return nil
+	}
+	tile := &renderer{img: image.NewRGBA(image.Rect(0, 0, w, h)), scale: r.scale, active: r.active}
+	tt := Identity().Scale(float64(w)/vals[2], float64(h)/vals[3]).Translate(-vals[0], -vals[1])
+	if err := tile.children(p, tt, ancestorStyle(p), nil); err != nil {
+		return nil
+	}
+
+	return func(x, y float64) (float64, float64, float64, float64) {
+		u, v := inv.Apply(x, y)
+		u = (u - vals[0]) / vals[2]
+		v = (v - vals[1]) / vals[3]
+		ix := int((u - math.Floor(u)) * float64(w))
+		iy := int((v - math.Floor(v)) * float64(h))
+		c := tile.img.RGBAAt(ix%w, iy%h)
+		return float64(c.R) / 255 * opacity, float64(c.G) / 255 * opacity, float64(c.B) / 255 * opacity, float64(c.A) / 255 * opacity
+	}
+}
+
+// Style of s from the root down, for content which is rendered out of place like markers and patterns
+func ancestorStyle(s *SVG) style {
+	var chain []*SVG
+	for g := s; g != nil; g = g.parent {
+		chain = append(chain, g)
+	}
+	st := initialStyle
+	for i := len(chain) - 1; i >= 0; i-- {
+		st = st.inherit(chain[i])
+	}
+	st.opacity = 1
+	return st
+}
+
+// Path data of basic shapes and paths
+func (s *SVG) shapePath() (*PathData, error) {
+	p := NewPathData()
+	switch s.tag {
+	case "path":
+		return s.pathData()
+	case "rect":
+		vals, err := s.nums("x", "y", "width", "height")
+		if err != nil {
+			return nil, err
+		}
+		x, y, w, h := vals[0], vals[1], vals[2], vals[3]
+		if w <= 0 || h <= 0 {
+			return p, nil
+		}
+		rx, errX := s.num("rx", -1)
+		ry, errY := s.num("ry", -1)
+		if errX != nil || errY != nil {
+			return nil, errors.New("Could not read corner radius of <rect>")
+		}
+		switch {
+		case rx < 0 && ry < 0:
+			rx, ry = 0, 0
+		case rx < 0:
+			rx = ry
+		case ry < 0:
+			ry = rx
+		}
+		rx, ry = math.Min(rx, w/2), math.Min(ry, h/2)
+		if rx == 0 || ry == 0 {
+			return p.MoveTo(x, y).HLine(x + w).VLine(y + h).HLine(x).Close(), nil
+		}
+		return p.MoveTo(x+rx, y).HLine(x+w-rx).ArcTo(rx, ry, 0, false, true, x+w, y+ry).
+			VLine(y+h-ry).ArcTo(rx, ry, 0, false, true, x+w-rx, y+h).
+			HLine(x+rx).ArcTo(rx, ry, 0, false, true, x, y+h-ry).
+			VLine(y+ry).ArcTo(rx, ry, 0, false, true, x+rx, y).Close(), nil
+	case "circle", "ellipse":
+		vals, err := s.nums("cx", "cy", "r", "rx", "ry")
+		if err != nil {
+			return nil, err
+		}
+		rx, ry := vals[2], vals[2]
+		if s.tag == "ellipse" {
+			rx, ry = vals[3], vals[4]
+		}
+		if rx <= 0 || ry <= 0 {
+			return p, nil
+		}
+		cx, cy := vals[0], vals[1]
+		return p.MoveTo(cx+rx, cy).ArcTo(rx, ry, 0, false, true, cx-rx, cy).ArcTo(rx, ry, 0, false, true, cx+rx, cy).Close(), nil
+	case "line":
+		vals, err := s.nums("x1", "y1", "x2", "y2")
+		if err != nil {
+			return nil, err
+		}
+		return p.MoveTo(vals[0], vals[1]).LineTo(vals[2], vals[3]), nil
+	case "polyline", "polygon":
+		vals, err := parseNumbers(fmt.Sprint(s.a["points"]))
+		if err != nil {
+			return nil, errors.New("Could not read points of <" + s.tag + ">: " + err.Error())
+		}
+		for i := 0; i+1 < len(vals); i += 2 {
+			if i == 0 {
+				p.MoveTo(vals[i], vals[i+1])
+			} else {
+				p.LineTo(vals[i], vals[i+1])
+			}
+		}
+		if s.tag == "polygon" && len(vals) >= 2 {
+			p.Close()
+		}
+		return p, nil
+	}
+	return nil, errors.New("<" + s.tag + "> is not a shape")
+}
+
+// Draw s with transform t to device space, parent style st and clip mask clip
+func (r *renderer) draw(s *SVG, t Transform, st style, clip []float32) error {
+	if v, ok := s.property("display"); (ok && v == "none") || notRendered[s.tag] {
+		return nil
+	}
+	st = st.inherit(s)
+
+	if s.tag == "svg" {
+		vp, err := s.viewport(0, 0)
+		if err != nil {
+			return err
+		}
+		// Nested svg clips its content to its viewport
+		if vals, err := s.nums("x", "y", "width", "height"); err == nil && vals[2] > 0 && vals[3] > 0 {
+			clip = r.mask(rasterize(rectPolygon(t, vals[0], vals[1], vals[2], vals[3]), false, r.img.Rect), clip)
+		}
+		return r.children(s, t.Multiply(vp), st, clip)
+	}
+
+	own, err := s.Transform()
+	if err != nil {
+		return err
+	}
+	t = t.Multiply(own)
+
+	if ref := s.reference(propertyOr(s, "clip-path", "")); ref != nil && ref.tag == "clipPath" {
+		if clip, err = r.clipPath(s, ref, t, clip); err != nil {
+			return err
+		}
+	}
+	if ref := s.reference(propertyOr(s, "mask", "")); ref != nil && ref.tag == "mask" {
+		if clip, err = r.maskOf(s, ref, t, clip); err != nil {
+			return err
+		}
+	}
+
+	switch s.tag {
+	case "path", "rect", "circle", "ellipse", "line", "polyline", "polygon":
+		return r.shape(s, t, st, clip)
+	case "text":
+		return r.text(s, t, st, clip)
+	case "use":
+		ref := s.href()
+		if ref == nil || r.active[ref] || (notRendered[ref.tag] && ref.tag != "symbol") {
+			return nil
+		}
+		vals, err := s.nums("x", "y", "width", "height")
+		if err != nil {
+			return err
+		}
+		r.active[ref] = true
+		defer delete(r.active, ref)
+		t = t.Translate(vals[0], vals[1])
+		if ref.tag == "symbol" {
+			vp, err := ref.viewport(vals[2], vals[3])
+			if err != nil {
+				return err
+			}
+			return r.children(ref, t.Multiply(vp), st.inherit(ref), clip)
+		}
+		return r.draw(ref, t, st, clip)
+	}
+	return r.children(s, t, st, clip)
+}
+
+func propertyOr(s *SVG, key, def string) string {
+	if v, ok := s.property(key); ok {
+		return v
+	}
+	return def
+}
+
+func (r *renderer) children(s *SVG, t Transform, st style, clip []float32) error {
+	for _, c := range s.mids {
+		if err := r.draw(c, t, st, clip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Intersect clip with the clipping path ref applied to s
+func (r *renderer) clipPath(s, ref *SVG, t Transform, clip []float32) ([]float32, error) {
+	ct := t
+	if units, _ := ref.property("clipPathUnits"); units == ObjectBoundingBox {
+		b, err := s.bounds(make(map[*SVG]bool))
+		if err != nil || !b.ok {
+			return clip, err
+		}
+		ct = ct.Multiply(boxTransform(b))
+	}
+	if v, ok := ref.a["transform"]; ok {
+		if tr, err := toTransform(v); err == nil {
+			ct = ct.Multiply(tr)
+		}
+	}
+
+	// Union of the outlines of the children
+	var polys [][]point
+	for _, c := range ref.mids {
+		if v, ok := c.property("display"); ok && v == "none" {
+			continue
+		}
+		own, err := c.Transform()
+		if err != nil {
+			return clip, err
+		}
+		var p *PathData
+		if c.tag == "text" {
+			p = textPath(c, ancestorStyle(c))
+		} else if p, err = c.shapePath(); err != nil {
+			continue
+		}
+		paths, _ := flatten(p.Normalize(), ct.Multiply(own))
+		for _, q := range paths {
+			if area(q) > 0 {
+				q = orient(q)
+			}
+			polys = append(polys, q)
+		}
+	}
+	return r.mask(rasterize(polys, false, r.img.Rect), clip), nil
+}
+
+// Intersect clip with the luminance of mask ref applied to s
+func (r *renderer) maskOf(s, ref *SVG, t Transform, clip []float32) ([]float32, error) {
+	mt := t
+	if units, _ := ref.property("maskContentUnits"); units == ObjectBoundingBox {
+		b, err := s.bounds(make(map[*SVG]bool))
+		if err != nil || !b.ok {
+			return clip, err
+		}
+		mt = mt.Multiply(boxTransform(b))
+	}
+	off := &renderer{img: image.NewRGBA(r.img.Rect), scale: r.scale, active: r.active}
+	if err := off.children(ref, mt, ancestorStyle(ref), nil); err != nil {
+		return clip, err
+	}
+	m := make([]float32, len(off.img.Pix)/4)
+	for i := range m {
+		p := off.img.Pix[4*i : 4*i+4]
+		// Luminance of premultiplied colour includes alpha
+		lum := (0.2125*float64(p[0]) + 0.7154*float64(p[1]) + 0.0721*float64(p[2])) / 255
+		m[i] = float32(lum)
+		if clip != nil {
+			m[i] *= clip[i]
+		}
+	}
+	return m, nil
+}
+
+// Fill and stroke shape s, then draw its markers
+func (r *renderer) shape(s *SVG, t Transform, st style, clip []float32) error {
+	p, err := s.shapePath()
+	if err != nil {
+		return err
+	}
+	n := p.Normalize()
+	if !st.visible {
+		return r.markers(s, n, t, st, clip)
+	}
+	box, err := s.bounds(make(map[*SVG]bool))
+	if err != nil {
+		return err
+	}
+
+	paths, closed := flatten(n, t)
+	if fill := r.paint(s, st.fill, st.fillOpacity, t, box); fill != nil {
+		r.composite(rasterize(paths, st.fillRule == "evenodd", r.img.Rect), fill, st.opacity, clip)
+	}
+
+	if stroke := r.paint(s, st.stroke, st.strokeOpacity, t, box); stroke != nil && st.strokeWidth > 0 {
+		width := st.strokeWidth * math.Sqrt(math.Abs(t.A*t.D-t.B*t.C))
+		if v, _ := s.property("vector-effect"); v == "non-scaling-stroke" {
+			width = st.strokeWidth * r.scale
+		}
+		outline := strokeOutline(paths, closed, strokeStyle{width, st.cap, st.join, st.miterLimit})
+		r.composite(rasterize(outline, false, r.img.Rect), stroke, st.opacity, clip)
+	}
+	return r.markers(s, n, t, st, clip)
+}
+
+// Marker placed on a vertex
+type placedMarker struct {
+	m             *SVG
+	t             Transform // From the marker viewport to user space of the marked element
+	content       Transform // From marker content to the marker viewport
+	width, height float64   // Size of the marker viewport
+	clip          bool      // Whether content is clipped to the viewport
+}
+
+// Place markers of s on vertices of its normalized path data n
+func (s *SVG) placeMarkers(n *PathData, st style) ([]placedMarker, error) {
+	if s.tag == "rect" || s.tag == "circle" || s.tag == "ellipse" {
+		return nil, nil
+	}
+	type vertex struct {
+		x, y    float64
+		in, out float64 // Directions in radians, NaN if none
+	}
+	var (
+		verts          []vertex
+		x, y           float64
+		startX, startY float64
+		subpathStart   int
+	)
+	dir := func(x0, y0, x1, y1 float64) float64 {
+		if x0 == x1 && y0 == y1 {
+			return math.NaN()
+		}
+		return math.Atan2(y1-y0, x1-x0)
+	}
+	for _, c := range n.Commands {
+		switch c.Command {
+		case 'M':
+			x, y = c.Args[0], c.Args[1]
+			startX, startY = x, y
+			subpathStart = len(verts)
+			verts = append(verts, vertex{x, y, math.NaN(), math.NaN()})
+		case 'L', 'C':
+			ex, ey := c.Args[len(c.Args)-2], c.Args[len(c.Args)-1]
+			out, in := dir(x, y, ex, ey), dir(x, y, ex, ey)
+			if c.Command == 'C' {
+				out = dir(x, y, c.Args[0], c.Args[1])
+				in = dir(c.Args[2], c.Args[3], ex, ey)
+			}
+			if len(verts) > 0 {
+				verts[len(verts)-1].out = out
+			}
+			x, y = ex, ey
+			verts = append(verts, vertex{x, y, in, math.NaN()})
+		case 'Z':
+			d := dir(x, y, startX, startY)
+			if len(verts) > 0 {
+				verts[len(verts)-1].out = d
+			}
+			verts = append(verts, vertex{startX, startY, d, verts[subpathStart].out})
+			x, y = startX, startY
+		}
+	}
+	var placed []placedMarker
+	for i, v := range verts {
+		value := st.markerMid
+		switch i {
+		case 0:
+			value = st.markerStart
+		case len(verts) - 1:
+			value = st.markerEnd
+		}
+		ref := s.reference(value)
+		if ref == nil || ref.tag != "marker" {
+			continue
+		}
+
+		// Bisect incoming and outgoing direction
+		angle := 0.0
+		if o, _ := ref.property("orient"); o == "auto" || o == "auto-start-reverse" {
+			switch {
+			case math.IsNaN(v.in):
+				angle = v.out
+			case math.IsNaN(v.out):
+				angle = v.in
+			default:
+				d := v.out - v.in
+				d = math.Atan2(math.Sin(d), math.Cos(d))
+				angle = v.in + d/2
+			}
+			if math.IsNaN(angle) {
+				angle = 0
+			}
+			if o == "auto-start-reverse" && i == 0 {
+				angle += math.Pi
+			}
+			angle *= 180 / math.Pi
+		} else if o != "" {
+			angle, _ = strconv.ParseFloat(o, 64)
+		}
+		pm, err := ref.placeMarker(Identity().Translate(v.x, v.y).Rotate(angle), st.strokeWidth)
+		if err != nil {
+			return nil, err
+		}
+		placed = append(placed, pm)
+	}
+	return placed, nil
+}
+
+// Place marker m with its reference point at the origin of t
+func (m *SVG) placeMarker(t Transform, strokeWidth float64) (placedMarker, error) {
+	pm := placedMarker{m: m, content: Identity()}
+	vals, err := m.nums("refX", "refY")
+	if err != nil {
+		return pm, err
+	}
+	if pm.width, err = m.num("markerWidth", 3); err != nil {
+		return pm, err
+	}
+	if pm.height, err = m.num("markerHeight", 3); err != nil {
+		return pm, err
+	}
+	if units, _ := m.property("markerUnits"); units != UserSpaceOnUse {
+		t = t.Scale(strokeWidth, strokeWidth)
+	}
+
+	if box, ok, err := m.viewBox(); err != nil {
+		return pm, err
+	} else if ok {
+		preserve := "xMidYMid meet"
+		if v, ok := m.a["preserveAspectRatio"]; ok {
+			preserve = fmt.Sprint(v)
+		}
+		pm.content = viewBoxTransform(box, 0, 0, pm.width, pm.height, preserve)
+	}
+	rx, ry := pm.content.Apply(vals[0], vals[1])
+	pm.t = t.Translate(-rx, -ry)
+	o, _ := m.property("overflow")
+	pm.clip = o != "visible" && o != "auto"
+	return pm, nil
+}
+
+// Draw markers of s with normalized path data n
+func (r *renderer) markers(s *SVG, n *PathData, t Transform, st style, clip []float32) error {
+	placed, err := s.placeMarkers(n, st)
+	if err != nil {
+		return err
+	}
+	for _, pm := range placed {
+		mt, mclip := t.Multiply(pm.t), clip
+		if pm.clip {
+			mclip = r.mask(rasterize(rectPolygon(mt, 0, 0, pm.width, pm.height), false, r.img.Rect), clip)
+		}
+		if err := r.children(pm.m, mt.Multiply(pm.content), ancestorStyle(pm.m), mclip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Outline of text of s in user space
+func textPath(s *SVG, st style) *PathData {
+	vals, _ := s.nums("x", "y")
+	x := vals[0]
+	width := textWidth(s.data) * st.fontSize
+	switch st.anchor {
+	case "middle":
+		x -= width / 2
+	case "end":
+		x -= width
+	}
+	p := NewPathData()
+	for _, rect := range textOutline(s.data) {
+		for i, q := range rect {
+			px, py := x+q.x*st.fontSize, vals[1]+q.y*st.fontSize
+			if i == 0 {
+				p.MoveTo(px, py)
+			} else {
+				p.LineTo(px, py)
+			}
+		}
+		p.Close()
+	}
+	return p
+}
+
+// Fill text of s with the embedded font
+func (r *renderer) text(s *SVG, t Transform, st style, clip []float32) error {
+	if !st.visible {
+		return nil
+	}
+	if _, err := s.nums("x", "y"); err != nil {
+		return err
+	}
+	p := textPath(s, st)
+	fill := r.paint(s, st.fill, st.fillOpacity, t, bounds{})
+	if fill == nil {
+		return nil
+	}
+	paths, _ := flatten(p, t)
+	r.composite(rasterize(paths, false, r.img.Rect), fill, st.opacity, clip)
+	return nil
+}
+
+// Render s to an image. scale gives the pixels per unit of the root viewBox, or of width and height if there is none.
+func (s *SVG) Render(scale float64) (*image.RGBA, error) {
+	if scale <= 0 {
+		return nil, errors.New("Scale must be positive")
+	}
+	vb, ok, err := s.viewBox()
+	if err != nil {
+		return nil, err
+	}
+	width, height, err := s.size(vb, ok)
+	if err != nil {
+		return nil, err
+	}
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("Could not find size of drawing from viewBox, width or height")
+	}
+	w, h := int(math.Ceil(width*scale)), int(math.Ceil(height*scale))
+	r := &renderer{img: image.NewRGBA(image.Rect(0, 0, w, h)), scale: scale, active: make(map[*SVG]bool)}
+
+	t := Identity().Scale(scale, scale)
+	if ok {
+		preserve := "xMidYMid meet"
+		if v, ok := s.a["preserveAspectRatio"]; ok {
+			preserve = fmt.Sprint(v)
+		}
+		t = viewBoxTransform(vb, 0, 0, float64(w), float64(h), preserve)
+	}
+	st := initialStyle.inherit(s)
+	if err := r.children(s, t, st, nil); err != nil {
+		return nil, err
+	}
+	return r.img, nil
+}
+
+// Size of root s from its width and height, where percentages are relative to viewBox vb if ok.
+// Lengths which can not be resolved fall back to the viewBox.
+func (s *SVG) size(vb [4]float64, ok bool) (width, height float64, err error) {
+	ref := [2]float64{math.NaN(), math.NaN()}
+	if ok {
+		ref = [2]float64{vb[2], vb[3]}
+	}
+	size := [2]float64{vb[2], vb[3]}
+	for i, key := range []string{"width", "height"} {
+		v, err := s.length(key, size[i], ref[i])
+		switch {
+		case err == nil:
+			size[i] = v
+		case !ok:
+			return 0, 0, err
+		}
+	}
+	return size[0], size[1], nil
+}
+
+// Render s and encode it as PNG to w
+func (s *SVG) RenderPNG(w io.Writer, scale float64) error {
+	img, err := s.Render(scale)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
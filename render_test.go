@@ -0,0 +1,89 @@
+package smartSVG
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func parseDoc(t *testing.T, size, content string) *SVG {
+	s, err := Parse(strings.NewReader(`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" ` + size + `>` + content + `</svg>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestRenderSize(t *testing.T) {
+	tests := []struct {
+		size string // Attributes of the root svg element
+		w, h int
+	}{
+		{`width="30" height="20"`, 30, 20},
+		{`viewBox="0 0 15 10"`, 15, 10},
+		{`width="1in" height="48px"`, 96, 48},
+		{`width="2cm" height="10mm"`, 76, 38},
+		{`width="50%" height="100%" viewBox="0 0 40 10"`, 20, 10},
+	}
+	for _, test := range tests {
+		img, err := parseDoc(t, test.size, "").Render(1)
+		if err != nil {
+			t.Errorf("Render of %s gave error %v", test.size, err)
+			continue
+		}
+		if b := img.Bounds(); b.Dx() != test.w || b.Dy() != test.h {
+			t.Errorf("Render of %s has size %dx%d, want %dx%d", test.size, b.Dx(), b.Dy(), test.w, test.h)
+		}
+	}
+	if _, err := parseDoc(t, `width="10"`, "").Render(1); err == nil {
+		t.Error("Render without height gave no error")
+	}
+	if _, err := parseDoc(t, `width="10" height="10"`, "").Render(0); err == nil {
+		t.Error("Render at scale 0 gave no error")
+	}
+}
+
+func TestRender(t *testing.T) {
+	s := parseDoc(t, `width="100" height="50" viewBox="0 0 200 100"`, `
+<defs>
+	<linearGradient id="fade"><stop offset="0" stop-color="red" /><stop offset="1" stop-color="blue" /></linearGradient>
+	<symbol id="dot" viewBox="0 0 10 10"><circle cx="5" cy="5" r="5" fill="lime" /></symbol>
+	<clipPath id="left"><rect width="100" height="100" /></clipPath>
+</defs>
+<rect width="200" height="20" fill="url(#fade)" />
+<g transform="translate(0, 40)"><rect width="40" height="20" fill="black" stroke="white" stroke-width="4" /></g>
+<use xlink:href="#dot" x="60" y="40" width="20" height="20" />
+<rect y="80" width="200" height="20" fill="blue" clip-path="url(#left)" />`)
+	var b bytes.Buffer
+	if err := s.RenderPNG(&b, 1); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{1, 5, color.RGBA{0xff, 0, 0, 0xff}},        // Start of gradient
+		{98, 5, color.RGBA{0, 0, 0xff, 0xff}},       // End of gradient
+		{10, 25, color.RGBA{0, 0, 0, 0xff}},         // Fill of translated rect
+		{0, 20, color.RGBA{0xff, 0xff, 0xff, 0xff}}, // Stroke of translated rect
+		{35, 25, color.RGBA{0, 0xff, 0, 0xff}},      // Symbol scaled into use
+		{25, 45, color.RGBA{0, 0, 0xff, 0xff}},      // Clipped rect inside clip path
+		{75, 45, color.RGBA{}},                      // Clipped rect outside clip path
+	}
+	for _, test := range tests {
+		if got := color.RGBAModel.Convert(img.At(test.x, test.y)).(color.RGBA); !closeRGBA(got, test.want) {
+			t.Errorf("Pixel at %d, %d is %v, want %v", test.x, test.y, got, test.want)
+		}
+	}
+}
+
+func closeRGBA(a, b color.RGBA) bool {
+	near := func(u, v uint8) bool { return int(u)-int(v) <= 8 && int(v)-int(u) <= 8 }
+	return near(a.R, b.R) && near(a.G, b.G) && near(a.B, b.B) && near(a.A, b.A)
+}
@@ -0,0 +1,146 @@
+package smartSVG
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Shapes of scatter markers
+type MarkerShape int
+
+const (
+	CircleMarker MarkerShape = iota
+	SquareMarker
+	TriangleMarker
+	DiamondMarker
+	CrossMarker
+)
+
+var markerShapeNames = []string{"circle", "square", "triangle", "diamond", "cross"}
+
+// Options of scatter plots. The zero value gives circles of the same size and colour.
+type ScatterOptions struct {
+	Shape   MarkerShape
+	Size    float64   // Width of markers in pixels. 0 gives 6.
+	Sizes   []float64 // Values giving the size of each point, if set. Areas are proportional to the values, with the largest one Size wide.
+	Colours []float64 // Values giving the colour of each point, if set, sampled from the lowest to the highest value
+	Jitter  float64   // Largest random offset of points in pixels, in both directions
+}
+
+// Add scatter plot of points d to diagram, drawn with one marker per point. Markers are not scaled with the diagram.
+// Colours from values are sampled from the palette of the diagram if it is sequential or diverging, and else from Viridis.
+func (s *SVG) AddScatter(d Data, opts ScatterOptions) (*SVG, error) {
+	if !s.isDiagram() {
+		return nil, errors.New("Will only add scatter plot to existing diagram")
+	}
+	data := s.part("data")
+	if data == nil {
+		return nil, errors.New("Could not find any existing data to add scatter plot with")
+	}
+	return s.scatter(data, d, s.NextColour().String(), opts)
+}
+
+// Draw markers of points d given in the coordinates of data, which is within the plot of diagram s
+func (s *SVG) scatter(data *SVG, d Data, colour string, opts ScatterOptions) (*SVG, error) {
+	n := len(d.X)
+	switch {
+	case n != len(d.Y):
+		return nil, errors.New("Got data pair with uneven length")
+	case n == 0:
+		return nil, errors.New("Got empty data set")
+	case len(opts.Sizes) != 0 && len(opts.Sizes) != n:
+		return nil, errors.New("Got " + fmt.Sprint(len(opts.Sizes)) + " sizes for " + fmt.Sprint(n) + " points")
+	case len(opts.Colours) != 0 && len(opts.Colours) != n:
+		return nil, errors.New("Got " + fmt.Sprint(len(opts.Colours)) + " colours for " + fmt.Sprint(n) + " points")
+	case opts.Shape < 0 || int(opts.Shape) >= len(markerShapeNames):
+		return nil, errors.New("Got unknown marker shape")
+	case opts.Size < 0 || opts.Jitter < 0:
+		return nil, errors.New("Size and jitter of markers must not be negative")
+	}
+	size := opts.Size
+	if size == 0 {
+		size = 6
+	}
+	var largest float64
+	for _, v := range opts.Sizes {
+		if v < 0 {
+			return nil, errors.New("Size " + fmt.Sprint(v) + " of point is negative")
+		}
+		largest = math.Max(largest, v)
+	}
+
+	// Markers are drawn in the viewport of the plot, so that they keep their shape when the data is scaled
+	viewport := data
+	for viewport.tag != "svg" && viewport.parent != nil {
+		viewport = viewport.parent
+	}
+	t, err := data.transformTo(viewport)
+	if err != nil {
+		return nil, err
+	}
+
+	palette := s.currentPalette()
+	if palette.Kind == Categorical {
+		palette = Viridis
+	}
+	var lo, hi float64
+	if len(opts.Colours) != 0 {
+		lo, hi = min(opts.Colours...), max(opts.Colours...)
+	}
+
+	id := s.markerSymbol(opts.Shape)
+	g := s.addSeries(viewport.G(Att{"class": "series", "fill": colour}))
+	for i := range d.X {
+		x, y := t.Apply(d.X[i], d.Y[i])
+		if opts.Jitter > 0 {
+			x += (2*s.random() - 1) * opts.Jitter
+			y += (2*s.random() - 1) * opts.Jitter
+		}
+		w := size
+		if len(opts.Sizes) != 0 && largest > 0 {
+			w = size * math.Sqrt(opts.Sizes[i]/largest)
+		}
+		u := g.Use(id, Att{"x": x - w/2, "y": y - w/2, "width": w, "height": w})
+		if len(opts.Colours) != 0 {
+			pos := 0.5
+			if hi > lo {
+				pos = (opts.Colours[i] - lo) / (hi - lo)
+			}
+			u.a["fill"] = palette.Sample(pos).String()
+		}
+	}
+	return g, nil
+}
+
+// Define symbol of marker shape in the defs of s, unless the document has it already. Returns id of symbol.
+// Shapes fill the box from -1 to 1, and take the fill of their use.
+func (s *SVG) markerSymbol(shape MarkerShape) string {
+	id := "scatter-" + markerShapeNames[shape]
+	if s.root().FindID(id) != nil {
+		return id
+	}
+	sym := s.Def().Symbol(id, Att{"viewBox": "-1 -1 2 2"})
+	switch shape {
+	case CircleMarker:
+		sym.CircleF(0, 0, 1, nil)
+	case SquareMarker:
+		sym.RectF(-0.8, -0.8, 1.6, 1.6, nil)
+	case TriangleMarker:
+		sym.Polygon(Data{[]float64{0, 0.866, -0.866}, []float64{-1, 0.5, 0.5}}, nil)
+	case DiamondMarker:
+		sym.Polygon(Data{[]float64{0, 1, 0, -1}, []float64{-1, 0, 1, 0}}, nil)
+	case CrossMarker:
+		// Plus with arms of half width a and length l, turned into an x
+		a, l := 0.3, 1.1
+		px := []float64{-a, a, a, l, l, a, a, -a, -a, -l, -l, -a}
+		py := []float64{-l, -l, -a, -a, a, a, l, l, a, a, -a, -a}
+		var cross Data
+		for i := range px {
+			x, y := Identity().Rotate(45).Apply(px[i], py[i])
+			cross.X, cross.Y = append(cross.X, x), append(cross.Y, y)
+		}
+		sym.Polygon(cross, nil)
+	}
+	return id
+}
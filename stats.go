@@ -0,0 +1,36 @@
+package smartSVG
+
+import (
+	"math"
+	"sort"
+)
+
+// Sorted copy of vals
+func sorted(vals []float64) []float64 {
+	s := append([]float64(nil), vals...)
+	sort.Float64s(s)
+	return s
+}
+
+// Quantile p between 0 and 1 of sorted values, interpolated linearly between neighbouring values
+func quantile(sorted []float64, p float64) float64 {
+	pos := p * float64(len(sorted)-1)
+	i := int(pos)
+	if i >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[i] + (pos-float64(i))*(sorted[i+1]-sorted[i])
+}
+
+// Sample standard deviation
+func stdDev(vals []float64) float64 {
+	if len(vals) < 2 {
+		return 0
+	}
+	mean := average(vals...)
+	var sum float64
+	for _, v := range vals {
+		sum += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sum / float64(len(vals)-1))
+}
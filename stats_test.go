@@ -0,0 +1,72 @@
+package smartSVG
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAverage(t *testing.T) {
+	tests := []struct {
+		vals []float64
+		want float64
+	}{
+		{[]float64{4}, 4},
+		{[]float64{1, 2, 3}, 2},
+		{[]float64{-1, 1}, 0},
+		{[]float64{1, 2, 3, 10}, 4},
+	}
+	for _, test := range tests {
+		if got := average(test.vals...); got != test.want {
+			t.Errorf("average(%v) = %v, want %v", test.vals, got, test.want)
+		}
+	}
+}
+
+func TestStdDev(t *testing.T) {
+	tests := []struct {
+		vals []float64
+		want float64
+	}{
+		{nil, 0},
+		{[]float64{5}, 0},
+		{[]float64{1, 2, 3}, 1},
+		{[]float64{2, 4, 4, 4, 5, 5, 7, 9}, math.Sqrt(32.0 / 7)},
+		{[]float64{3, 3, 3}, 0},
+	}
+	for _, test := range tests {
+		if got := stdDev(test.vals); math.Abs(got-test.want) > 1e-12 {
+			t.Errorf("stdDev(%v) = %v, want %v", test.vals, got, test.want)
+		}
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	vals := sorted([]float64{4, 1, 3, 2, 5})
+	tests := []struct {
+		p, want float64
+	}{
+		{0, 1},
+		{0.25, 2},
+		{0.5, 3},
+		{0.6, 3.4},
+		{1, 5},
+	}
+	for _, test := range tests {
+		if got := quantile(vals, test.p); math.Abs(got-test.want) > 1e-12 {
+			t.Errorf("quantile(%v, %v) = %v, want %v", vals, test.p, got, test.want)
+		}
+	}
+	if got := quantile([]float64{7}, 0.5); got != 7 {
+		t.Errorf("quantile of one value = %v, want 7", got)
+	}
+}
+
+func TestSorted(t *testing.T) {
+	vals := []float64{3, 1, 2}
+	if got := sorted(vals); got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("sorted(%v) = %v", vals, got)
+	}
+	if vals[0] != 3 {
+		t.Error("sorted changed its argument")
+	}
+}
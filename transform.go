@@ -0,0 +1,225 @@
+package smartSVG
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 2D affine transform, written as matrix(A, B, C, D, E, F). Maps x, y to A*x + C*y + E, B*x + D*y + F.
+// Ref http://www.w3.org/TR/SVG11/coords.html#TransformAttribute
+// The methods return the transform followed by the given one, in the order of a transform list.
+type Transform struct {
+	A, B, C, D, E, F float64
+}
+
+// Transform leaving the coordinate system unchanged
+func Identity() Transform {
+	return Transform{A: 1, D: 1}
+}
+
+// Transform given by matrix(a, b, c, d, e, f)
+func Matrix(a, b, c, d, e, f float64) Transform {
+	return Transform{a, b, c, d, e, f}
+}
+
+// Apply u after t, as the transform list "t u"
+func (t Transform) Multiply(u Transform) Transform {
+	return Transform{
+		A: t.A*u.A + t.C*u.B,
+		B: t.B*u.A + t.D*u.B,
+		C: t.A*u.C + t.C*u.D,
+		D: t.B*u.C + t.D*u.D,
+		E: t.A*u.E + t.C*u.F + t.E,
+		F: t.B*u.E + t.D*u.F + t.F,
+	}
+}
+
+func (t Transform) Translate(x, y float64) Transform {
+	return t.Multiply(Transform{A: 1, D: 1, E: x, F: y})
+}
+
+func (t Transform) Scale(x, y float64) Transform {
+	return t.Multiply(Transform{A: x, D: y})
+}
+
+// Rotate by angle degrees about origin
+func (t Transform) Rotate(angle float64) Transform {
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+
+	// Keep right angles exact
+	if math.Mod(angle, 90) == 0 {
+		sin, cos = math.Round(sin), math.Round(cos)
+	}
+	return t.Multiply(Transform{A: cos, B: sin, C: -sin, D: cos})
+}
+
+// Rotate by angle degrees about cx, cy
+func (t Transform) RotateAbout(angle, cx, cy float64) Transform {
+	return t.Translate(cx, cy).Rotate(angle).Translate(-cx, -cy)
+}
+
+// Skew along x axis by angle degrees
+func (t Transform) SkewX(angle float64) Transform {
+	return t.Multiply(Transform{A: 1, C: math.Tan(angle * math.Pi / 180), D: 1})
+}
+
+// Skew along y axis by angle degrees
+func (t Transform) SkewY(angle float64) Transform {
+	return t.Multiply(Transform{A: 1, B: math.Tan(angle * math.Pi / 180), D: 1})
+}
+
+// Transform undoing t
+func (t Transform) Invert() (Transform, error) {
+	det := t.A*t.D - t.B*t.C
+	if det == 0 {
+		return Transform{}, errors.New("Transform " + t.String() + " is not invertible")
+	}
+	return Transform{
+		A: t.D / det,
+		B: -t.B / det,
+		C: -t.C / det,
+		D: t.A / det,
+		E: (t.C*t.F - t.D*t.E) / det,
+		F: (t.B*t.E - t.A*t.F) / det,
+	}, nil
+}
+
+// Map point x, y through t
+func (t Transform) Apply(x, y float64) (float64, float64) {
+	return t.A*x + t.C*y + t.E, t.B*x + t.D*y + t.F
+}
+
+// Encode transform to canonical form. Translations, scales and rotations are written as such, other transforms as matrix.
+func (t Transform) String() string {
+	return t.format(exactFormat)
+}
+
+// Encode transform with numbers written by f
+func (t Transform) format(f NumberFormat) string {
+	args := func(vals ...float64) string {
+		strs := make([]string, len(vals))
+		for i, v := range vals {
+			strs[i] = f.Format(v)
+		}
+		return strings.Join(strs, ", ")
+	}
+	translate := "translate(" + args(t.E, t.F) + ")"
+	scale := "scale(" + args(t.A, t.D) + ")"
+	switch {
+	case t.B != 0 && t.A == t.D && t.B == -t.C && math.Abs(t.A*t.A+t.B*t.B-1) < 1e-12:
+		// Rotation about the point which it leaves in place
+		angle := math.Atan2(t.B, t.A) * 180 / math.Pi
+		if r := math.Round(angle); math.Abs(angle-r) < 1e-9 {
+			angle = r
+		}
+		if t.E == 0 && t.F == 0 {
+			return "rotate(" + args(angle) + ")"
+		}
+		det := 2 - 2*t.A
+		cx := ((1-t.A)*t.E - t.B*t.F) / det
+		cy := (t.B*t.E + (1-t.A)*t.F) / det
+		return "rotate(" + args(angle, cx, cy) + ")"
+	case t.B != 0 || t.C != 0:
+		return "matrix(" + args(t.A, t.B, t.C, t.D, t.E, t.F) + ")"
+	case t.A == 1 && t.D == 1:
+		return translate
+	case t.E == 0 && t.F == 0:
+		return scale
+	}
+	return translate + " " + scale
+}
+
+// Parse transform list of the transform attribute
+func ParseTransform(str string) (Transform, error) {
+	t := Identity()
+	rest := strings.TrimSpace(str)
+	for rest != "" {
+		start := strings.Index(rest, "(")
+		end := strings.Index(rest, ")")
+		if start == -1 || end < start {
+			return t, errors.New("Could not parse transform: " + str)
+		}
+		name := strings.TrimSpace(rest[:start])
+		fields := strings.FieldsFunc(rest[start+1:end], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+		})
+		rest = strings.TrimLeft(rest[end+1:], ", \t\n\r")
+
+		args := make([]float64, len(fields))
+		for i, f := range fields {
+			var err error
+			if args[i], err = strconv.ParseFloat(f, 64); err != nil {
+				return t, errors.New("Could not parse argument of " + name + " in transform: " + str)
+			}
+		}
+
+		// Optional arguments
+		switch {
+		case name == "translate" && len(args) == 1:
+			args = append(args, 0)
+		case name == "scale" && len(args) == 1:
+			args = append(args, args[0])
+		}
+
+		switch {
+		case name == "matrix" && len(args) == 6:
+			t = t.Multiply(Matrix(args[0], args[1], args[2], args[3], args[4], args[5]))
+		case name == "translate" && len(args) == 2:
+			t = t.Translate(args[0], args[1])
+		case name == "scale" && len(args) == 2:
+			t = t.Scale(args[0], args[1])
+		case name == "rotate" && len(args) == 1:
+			t = t.Rotate(args[0])
+		case name == "rotate" && len(args) == 3:
+			t = t.RotateAbout(args[0], args[1], args[2])
+		case name == "skewX" && len(args) == 1:
+			t = t.SkewX(args[0])
+		case name == "skewY" && len(args) == 1:
+			t = t.SkewY(args[0])
+		default:
+			return t, errors.New("Unknown transform " + name + " with " + fmt.Sprint(len(args)) + " arguments in: " + str)
+		}
+	}
+	return t, nil
+}
+
+// Attributes holding transform lists
+func isTransform(key string) bool {
+	return key == "transform" || key == "gradientTransform" || key == "patternTransform"
+}
+
+// Read transform from attribute value
+func toTransform(v interface{}) (Transform, error) {
+	if t, ok := v.(Transform); ok {
+		return t, nil
+	}
+	return ParseTransform(fmt.Sprint(v))
+}
+
+// Transform of group, the identity if none is set
+func (s *SVG) Transform() (Transform, error) {
+	v, ok := s.a["transform"]
+	if !ok {
+		return Identity(), nil
+	}
+	return toTransform(v)
+}
+
+// Transform from the coordinates of s to those of its ancestor
+func (s *SVG) transformTo(ancestor *SVG) (Transform, error) {
+	t := Identity()
+	for g := s; g != ancestor; g = g.parent {
+		if g == nil {
+			return t, errors.New("Group is not within the given ancestor")
+		}
+		u, err := g.Transform()
+		if err != nil {
+			return t, err
+		}
+		t = u.Multiply(t)
+	}
+	return t, nil
+}
@@ -0,0 +1,95 @@
+package smartSVG
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTransformString(t *testing.T) {
+	tests := []struct {
+		t    Transform
+		want string
+	}{
+		{Identity(), "translate(0, 0)"},
+		{Identity().Translate(3, -4), "translate(3, -4)"},
+		{Identity().Scale(2, 0.5), "scale(2, 0.5)"},
+		{Identity().Translate(1, 2).Scale(3, 4), "translate(1, 2) scale(3, 4)"},
+		{Identity().Rotate(90), "rotate(90)"},
+		{Identity().Rotate(-30), "rotate(-30)"},
+		{Identity().RotateAbout(90, 10, 20), "rotate(90, 10, 20)"},
+		{Identity().Translate(4, 0).Rotate(90), "rotate(90, 2, 2)"},
+		{Identity().Rotate(30).Scale(2, 2), "matrix(1.7320508075688774, 0.9999999999999999, -0.9999999999999999, 1.7320508075688774, 0, 0)"},
+		{Identity().SkewX(45), "matrix(1, 0, 1, 1, 0, 0)"},
+	}
+	for _, test := range tests {
+		if got := test.t.String(); got != test.want {
+			t.Errorf("%#v is written as %v, want %v", test.t, got, test.want)
+		}
+	}
+}
+
+// Whether the coefficients of t and u differ by less than 1e-9
+func nearTransform(t, u Transform) bool {
+	a := []float64{t.A, t.B, t.C, t.D, t.E, t.F}
+	b := []float64{u.A, u.B, u.C, u.D, u.E, u.F}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseTransform(t *testing.T) {
+	tests := []struct {
+		str  string
+		want Transform
+	}{
+		{"", Identity()},
+		{"translate(10)", Identity().Translate(10, 0)},
+		{"translate(10 20) scale(2)", Identity().Translate(10, 20).Scale(2, 2)},
+		{"rotate(90, 10, 20)", Identity().RotateAbout(90, 10, 20)},
+		{"matrix(1,2,3,4,5,6)", Matrix(1, 2, 3, 4, 5, 6)},
+		{"skewX(30),skewY(10)", Identity().SkewX(30).SkewY(10)},
+		{"\ttranslate( 1 ,2 )\n", Identity().Translate(1, 2)},
+	}
+	for _, test := range tests {
+		got, err := ParseTransform(test.str)
+		if err != nil {
+			t.Errorf("ParseTransform(%q) gave error %v", test.str, err)
+		} else if !nearTransform(got, test.want) {
+			t.Errorf("ParseTransform(%q) = %v, want %v", test.str, got, test.want)
+		}
+
+		// Written transforms read back as the same transform
+		if again, err := ParseTransform(test.want.String()); err != nil || !nearTransform(again, test.want) {
+			t.Errorf("ParseTransform(%q) = %v, %v, want %v", test.want.String(), again, err, test.want)
+		}
+	}
+	for _, str := range []string{"translate(1", "rotate(1, 2)", "scale(a)", "spin(3)"} {
+		if _, err := ParseTransform(str); err == nil {
+			t.Errorf("ParseTransform(%q) gave no error", str)
+		}
+	}
+}
+
+func TestTransformApply(t *testing.T) {
+	tr := Identity().Translate(10, 0).RotateAbout(90, 1, 1).Scale(2, 3)
+	inv, err := tr.Invert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, y := tr.Apply(1, 2)
+	if x, y = inv.Apply(x, y); math.Abs(x-1) > 1e-12 || math.Abs(y-2) > 1e-12 {
+		t.Errorf("Inverse maps point back to %v, %v, want 1, 2", x, y)
+	}
+	if x, y := Identity().RotateAbout(90, 1, 1).Apply(2, 1); x != 1 || y != 2 {
+		t.Errorf("Rotation about 1, 1 maps 2, 1 to %v, %v, want 1, 2", x, y)
+	}
+	if !nearTransform(tr.Multiply(inv), Identity()) {
+		t.Errorf("Transform times its inverse is %v", tr.Multiply(inv))
+	}
+	if _, err := Identity().Scale(0, 1).Invert(); err == nil {
+		t.Error("Inverting a singular transform gave no error")
+	}
+}
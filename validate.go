@@ -0,0 +1,270 @@
+package smartSVG
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Problem found by Validate. Path locates the node like /svg/g[2]/rect[1], counting siblings of the same tag from 1.
+type Issue struct {
+	Path    string
+	Message string
+}
+
+func (i Issue) String() string {
+	return i.Path + ": " + i.Message
+}
+
+// Element categories of the content model. Ref http://www.w3.org/TR/SVG11/intro.html#TermAnimationElement
+var (
+	descriptiveElements = []string{"desc", "title", "metadata"}
+	animationElements   = []string{"animate", "animateColor", "animateMotion", "animateTransform", "set"}
+	shapeElements       = []string{"circle", "ellipse", "line", "path", "polygon", "polyline", "rect"}
+	structuralElements  = []string{"defs", "g", "svg", "symbol", "use"}
+	gradientElements    = []string{"linearGradient", "radialGradient"}
+	lightSources        = []string{"feDistantLight", "fePointLight", "feSpotLight"}
+	filterPrimitives    = []string{"feBlend", "feColorMatrix", "feComponentTransfer", "feComposite", "feConvolveMatrix",
+		"feDiffuseLighting", "feDisplacementMap", "feDropShadow", "feFlood", "feGaussianBlur", "feImage", "feMerge",
+		"feMorphology", "feOffset", "feSpecularLighting", "feTile", "feTurbulence"}
+	containerContent = concat(animationElements, descriptiveElements, shapeElements, structuralElements, gradientElements,
+		[]string{"a", "clipPath", "color-profile", "cursor", "filter", "font", "font-face", "foreignObject", "image",
+			"marker", "mask", "pattern", "script", "style", "switch", "text", "view"})
+	textContent = concat(descriptiveElements, []string{"a", "altGlyph", "animate", "animateColor", "set", "tref", "tspan"})
+)
+
+func concat(lists ...[]string) (all []string) {
+	for _, l := range lists {
+		all = append(all, l...)
+	}
+	return
+}
+
+// Allowed children of each known element. Children of foreignObject are not checked.
+var contentModel = map[string][]string{
+	"svg": containerContent, "g": containerContent, "defs": containerContent, "symbol": containerContent,
+	"marker": containerContent, "mask": containerContent, "pattern": containerContent,
+	"a": concat(containerContent, []string{"altGlyph", "textPath", "tref", "tspan"}),
+	"switch": concat(animationElements, descriptiveElements, shapeElements,
+		[]string{"a", "foreignObject", "g", "image", "svg", "switch", "text", "use"}),
+	"clipPath":            concat(animationElements, descriptiveElements, shapeElements, []string{"text", "use"}),
+	"text":                concat(textContent, animationElements, []string{"textPath"}),
+	"tspan":               textContent,
+	"textPath":            textContent,
+	"tref":                concat(descriptiveElements, []string{"animate", "animateColor", "set"}),
+	"altGlyph":            nil,
+	"use":                 concat(animationElements, descriptiveElements),
+	"image":               concat(animationElements, descriptiveElements),
+	"linearGradient":      concat(descriptiveElements, []string{"animate", "animateTransform", "set", "stop"}),
+	"radialGradient":      concat(descriptiveElements, []string{"animate", "animateTransform", "set", "stop"}),
+	"stop":                []string{"animate", "animateColor", "set"},
+	"filter":              concat(descriptiveElements, filterPrimitives, []string{"animate", "set"}),
+	"feMerge":             []string{"feMergeNode", "animate", "set"},
+	"feComponentTransfer": []string{"feFuncR", "feFuncG", "feFuncB", "feFuncA"},
+	"feDiffuseLighting":   concat(descriptiveElements, lightSources),
+	"feSpecularLighting":  concat(descriptiveElements, lightSources),
+	"animateMotion":       concat(descriptiveElements, []string{"mpath"}),
+	"mpath":               descriptiveElements,
+	"view":                descriptiveElements,
+	"desc":                nil, "title": nil, "metadata": nil, "style": nil, "script": nil, "foreignObject": nil,
+	"font": nil, "font-face": nil, "color-profile": nil, "cursor": nil,
+}
+
+func init() {
+	for _, tag := range shapeElements {
+		contentModel[tag] = concat(animationElements, descriptiveElements)
+	}
+	for _, tag := range concat(filterPrimitives, lightSources,
+		[]string{"feMergeNode", "feFuncR", "feFuncG", "feFuncB", "feFuncA"}) {
+		if _, ok := contentModel[tag]; !ok {
+			contentModel[tag] = []string{"animate", "set"}
+		}
+	}
+	for _, tag := range animationElements {
+		if tag != "animateMotion" {
+			contentModel[tag] = descriptiveElements
+		}
+	}
+}
+
+// Elements which may hold character data
+var characterData = map[string]bool{
+	"text": true, "tspan": true, "textPath": true, "tref": true, "altGlyph": true, "a": true,
+	"title": true, "desc": true, "metadata": true, "style": true, "script": true, "foreignObject": true,
+}
+
+// Required attributes of elements. Each requirement lists alternative names.
+var requiredAttributes = map[string][][]string{
+	"circle":            {{"r"}},
+	"ellipse":           {{"rx"}, {"ry"}},
+	"rect":              {{"width"}, {"height"}},
+	"path":              {{"d"}},
+	"polygon":           {{"points"}},
+	"polyline":          {{"points"}},
+	"image":             {{"width"}, {"height"}, {"xlink:href", "href"}},
+	"use":               {{"xlink:href", "href"}},
+	"mpath":             {{"xlink:href", "href"}},
+	"textPath":          {{"xlink:href", "href"}},
+	"tref":              {{"xlink:href", "href"}},
+	"stop":              {{"offset"}},
+	"animate":           {{"attributeName"}},
+	"animateColor":      {{"attributeName"}},
+	"animateTransform":  {{"attributeName"}},
+	"set":               {{"attributeName"}, {"to"}},
+	"feBlend":           {{"in2"}},
+	"feComposite":       {{"in2"}},
+	"feDisplacementMap": {{"in2"}},
+	"feFuncR":           {{"type"}},
+	"feFuncG":           {{"type"}},
+	"feFuncB":           {{"type"}},
+	"feFuncA":           {{"type"}},
+}
+
+// Lengths which must not be negative
+var nonNegative = map[string][]string{
+	"circle": {"r"}, "ellipse": {"rx", "ry"}, "rect": {"width", "height", "rx", "ry"},
+	"image": {"width", "height"}, "use": {"width", "height"}, "svg": {"width", "height"},
+	"pattern": {"width", "height"}, "marker": {"markerWidth", "markerHeight"},
+}
+
+// Properties referencing other elements by url(#id), with the elements they may reference
+var urlReferences = map[string][]string{
+	"fill":         {"linearGradient", "radialGradient", "pattern"},
+	"stroke":       {"linearGradient", "radialGradient", "pattern"},
+	"clip-path":    {"clipPath"},
+	"mask":         {"mask"},
+	"filter":       {"filter"},
+	"marker":       {"marker"},
+	"marker-start": {"marker"},
+	"marker-mid":   {"marker"},
+	"marker-end":   {"marker"},
+}
+
+// Check s against the content model: allowed children, required attributes, unique ids and resolvable references.
+// Elements with a namespace prefix are not checked.
+func (s *SVG) Validate() []Issue {
+	var issues []Issue
+	ids := make(map[string]*SVG)
+	paths := make(map[*SVG]string)
+
+	// Find paths and ids first, as references may point forward
+	var index func(g *SVG, path string)
+	index = func(g *SVG, path string) {
+		paths[g] = path
+		if v, ok := g.a["id"]; ok {
+			id := fmt.Sprint(v)
+			if first, ok := ids[id]; ok {
+				issues = append(issues, Issue{path, "Duplicate id \"" + id + "\", first used by " + paths[first]})
+			} else {
+				ids[id] = g
+			}
+		}
+		count := make(map[string]int)
+		for _, c := range g.mids {
+			count[c.tag]++
+			index(c, path+"/"+c.tag+"["+strconv.Itoa(count[c.tag])+"]")
+		}
+	}
+	index(s, "/"+s.tag)
+
+	var check func(g *SVG)
+	check = func(g *SVG) {
+		path := paths[g]
+		allowed, known := contentModel[g.tag]
+		switch {
+		case strings.Contains(g.tag, ":"):
+			return
+		case !known:
+			issues = append(issues, Issue{path, "Unknown element <" + g.tag + ">"})
+		case g.tag != "foreignObject":
+			for _, c := range g.mids {
+				// Unknown children are reported by themselves
+				if _, known := contentModel[c.tag]; known && !contains(allowed, c.tag) {
+					issues = append(issues, Issue{paths[c], "<" + c.tag + "> is not allowed in <" + g.tag + ">"})
+				}
+			}
+			if len(g.mids) == 0 && strings.TrimSpace(g.data) != "" && !characterData[g.tag] {
+				issues = append(issues, Issue{path, "Character data is not allowed in <" + g.tag + ">"})
+			}
+		}
+
+		for _, req := range requiredAttributes[g.tag] {
+			found := false
+			for _, name := range req {
+				if _, ok := g.a[name]; ok {
+					found = true
+				}
+			}
+			if !found {
+				issues = append(issues, Issue{path, "Missing required attribute " + req[0] + " of <" + g.tag + ">"})
+			}
+		}
+		for _, key := range nonNegative[g.tag] {
+			// Percentages have the sign of their number, whatever they are relative to
+			if v, err := g.length(key, 0, 1); err != nil {
+				issues = append(issues, Issue{path, err.Error()})
+			} else if v < 0 {
+				issues = append(issues, Issue{path, "Negative " + key + " of <" + g.tag + ">"})
+			}
+		}
+
+		// Links by href must resolve, and use must not reference its own ancestor
+		for _, key := range []string{"xlink:href", "href"} {
+			v, ok := g.a[key]
+			if !ok || !strings.HasPrefix(fmt.Sprint(v), "#") {
+				continue
+			}
+			id := strings.TrimPrefix(fmt.Sprint(v), "#")
+			switch ref, ok := ids[id]; {
+			case !ok:
+				issues = append(issues, Issue{path, "Reference " + key + "=\"#" + id + "\" has no target"})
+			case g.tag == "use" && (ref == g || g.IsParent(ref)):
+				issues = append(issues, Issue{path, "<use> references its own ancestor " + paths[ref]})
+			}
+		}
+		for _, key := range sortedKeys(urlReferences) {
+			v, ok := g.property(key)
+			if !ok || !strings.HasPrefix(v, "url(") {
+				continue
+			}
+			end := strings.Index(v, ")")
+			if end == -1 {
+				issues = append(issues, Issue{path, "Could not read reference " + key + "=\"" + v + "\""})
+				continue
+			}
+			id := strings.TrimPrefix(strings.Trim(strings.TrimSpace(v[4:end]), `"'`), "#")
+			if ref, ok := ids[id]; !ok {
+				issues = append(issues, Issue{path, "Reference " + key + "=\"" + v + "\" has no target"})
+			} else if !contains(urlReferences[key], ref.tag) {
+				issues = append(issues, Issue{path, "Reference " + key + "=\"" + v + "\" is <" + ref.tag + ">, not " +
+					strings.Join(urlReferences[key], " or ")})
+			}
+		}
+
+		for _, c := range g.mids {
+			check(c)
+		}
+	}
+	check(s)
+	return issues
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys of m in sorted order, so that issues are reported in a stable order
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
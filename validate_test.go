@@ -0,0 +1,42 @@
+package smartSVG
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		doc  string // Content of the root svg element
+		want []string
+	}{
+		{`<rect width="10" height="10" />`, nil},
+		{`<rect width="100%" height="10mm" /><circle r="1em" />`, nil},
+		{`<rect width="-1" height="1" />`, []string{"Negative width of <rect>"}},
+		{`<rect width="-5%" height="1" />`, []string{"Negative width of <rect>"}},
+		{`<rect width="wide" height="1" />`, []string{"Could not read width of <rect>: wide"}},
+		{`<circle />`, []string{"Missing required attribute r of <circle>"}},
+		{`<blink />`, []string{"Unknown element <blink>"}},
+		{`<rect width="1" height="1"><circle r="1" /></rect>`, []string{"<circle> is not allowed in <rect>"}},
+		{`<g>text</g>`, []string{"Character data is not allowed in <g>"}},
+		{`<g id="a" /><g id="a" />`, []string{`Duplicate id "a", first used by /svg/g[1]`}},
+		{`<use xlink:href="#nope" />`, []string{`Reference xlink:href="#nope" has no target`}},
+		{`<g id="a"><use xlink:href="#a" /></g>`, []string{"<use> references its own ancestor /svg/g[1]"}},
+		{`<rect width="1" height="1" fill="url(#nope)" />`, []string{`Reference fill="url(#nope)" has no target`}},
+		{`<g id="a" /><rect width="1" height="1" fill="url(#a)" />`, []string{`Reference fill="url(#a)" is <g>, not linearGradient or radialGradient or pattern`}},
+	}
+	for _, test := range tests {
+		s, err := Parse(strings.NewReader(`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">` + test.doc + `</svg>`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, issue := range s.Validate() {
+			got = append(got, issue.Message)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Validate of %s = %q, want %q", test.doc, got, test.want)
+		}
+	}
+}